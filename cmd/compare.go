@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/tkozakas/gh-log/internal/app"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare owner/repo base...head",
+	Short: "Show the commits and changed files in head that are not in base",
+	Long:  "Compare two branches, tags or commits of a repository: how far head is ahead of and behind base, the commits only head has, and the files they change.",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runCompare,
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	base, head, err := models.ParseCompareSpec(args[1], "")
+	if err != nil {
+		return err
+	}
+
+	_, cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	client, opts, closeStore, err := openProvider(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	opts = append(opts, app.WithCompare(args[0], base, head))
+	p := tea.NewProgram(app.New(ctx, client, opts...), tea.WithAltScreen())
+	if _, err := p.Run(); err != nil {
+		return err
+	}
+	return nil
+}
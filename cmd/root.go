@@ -1,13 +1,29 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
 	"github.com/tkozakas/gh-log/internal/app"
+	"github.com/tkozakas/gh-log/internal/cache"
+	"github.com/tkozakas/gh-log/internal/config"
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/git"
+	"github.com/tkozakas/gh-log/internal/gitea"
 	"github.com/tkozakas/gh-log/internal/github"
+	"github.com/tkozakas/gh-log/internal/gitlab"
+	"github.com/tkozakas/gh-log/internal/models"
+	"github.com/tkozakas/gh-log/internal/store"
+	"github.com/tkozakas/gh-log/internal/workspace"
 )
 
 var rootCmd = &cobra.Command{
@@ -17,21 +33,276 @@ var rootCmd = &cobra.Command{
 	RunE:  run,
 }
 
+var (
+	noCache       bool
+	offline       bool
+	local         bool
+	localPaths    []string
+	hostnames     []string
+	sources       []string
+	orgs          []string
+	gitlabURLs    []string
+	giteaURLs     []string
+	cacheTTL      time.Duration
+	workspaceName string
+)
+
+func init() {
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Disable the on-disk HTTP cache and local commit store")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "Browse previously cached repositories and commits without network access")
+	rootCmd.PersistentFlags().BoolVar(&local, "local", false, "Read history from the git repository in the current directory")
+	rootCmd.PersistentFlags().StringSliceVar(&localPaths, "path", nil, "Read history from local git clones instead of the API (repeatable)")
+	rootCmd.PersistentFlags().StringSliceVar(&hostnames, "hostname", nil, "GitHub or GitHub Enterprise Server host to list repositories from (repeatable, overrides the config file)")
+	rootCmd.Flags().StringSliceVar(&sources, "source", nil, "GitHub repository sources to list: owner, org, team, collaborator, starred (default all)")
+	rootCmd.Flags().StringSliceVar(&orgs, "org", nil, "Only list organization and team repositories from these organizations (repeatable)")
+	rootCmd.PersistentFlags().StringSliceVar(&gitlabURLs, "gitlab", nil, "Also list projects from this GitLab host, authenticated with GITLAB_TOKEN (repeatable)")
+	rootCmd.PersistentFlags().StringSliceVar(&giteaURLs, "gitea", nil, "Also list repositories from this Gitea host, authenticated with GITEA_TOKEN (repeatable)")
+	rootCmd.Flags().StringVar(&workspaceName, "workspace", "", "Open a saved workspace and skip straight to its commits")
+	rootCmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", github.DefaultCacheTTL, "Serve cached responses without revalidating for this long")
+}
+
 func Execute() error {
 	return rootCmd.Execute()
 }
 
 func run(cmd *cobra.Command, args []string) error {
-	if err := github.CheckGHInstalled(); err != nil {
-		return fmt.Errorf("gh CLI is required: %w", err)
+	isLocal := local || len(localPaths) > 0
+	if isLocal && workspaceName != "" {
+		return errors.New("--workspace cannot be combined with --local or --path")
 	}
-	if err := github.CheckGHAuthenticated(); err != nil {
-		return fmt.Errorf("gh CLI not authenticated, run 'gh auth login': %w", err)
+
+	cfgPath, cfg, err := loadConfig()
+	if err != nil {
+		return err
 	}
 
-	p := tea.NewProgram(app.New(), tea.WithAltScreen())
+	ctx, cancel := context.WithCancel(cmd.Context())
+	defer cancel()
+
+	client, opts, closeStore, err := openProvider(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	if !isLocal {
+		opts = append(opts, app.WithSavedRepos(cfg.Repos, func(ids []string) error {
+			if cfgPath == "" {
+				return errors.New("no config directory")
+			}
+			cfg.Repos = ids
+			return config.Save(cfgPath, cfg)
+		}))
+
+		workspaceOpts, err := workspaceOptions()
+		if err != nil {
+			return err
+		}
+		opts = append(opts, workspaceOpts...)
+	}
+
+	p := tea.NewProgram(app.New(ctx, client, opts...), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		return err
 	}
 	return nil
 }
+
+func openProvider(ctx context.Context, cfg config.Config) (forge.Provider, []app.Option, func(), error) {
+	noop := func() {}
+	if offline && noCache {
+		return nil, nil, noop, errors.New("--offline reads from the local cache and cannot be combined with --no-cache")
+	}
+
+	switch {
+	case local || len(localPaths) > 0:
+		paths := localPaths
+		if len(paths) == 0 {
+			paths = []string{"."}
+		}
+		repos, err := git.Open(ctx, paths...)
+		if err != nil {
+			return nil, nil, noop, err
+		}
+		return repos, nil, noop, nil
+	case offline:
+		commits, err := openStore()
+		if err != nil {
+			return nil, nil, noop, err
+		}
+		cachedAt, _ := commits.RepositoriesFetchedAt(ctx)
+		return store.NewOfflineClient(commits), []app.Option{app.WithOffline(cachedAt)}, func() { commits.Close() }, nil
+	default:
+		client, err := newClient(cfg)
+		if err != nil {
+			return nil, nil, noop, err
+		}
+		if noCache {
+			return client, nil, noop, nil
+		}
+		commits, err := openStore()
+		if err != nil {
+			return nil, nil, noop, err
+		}
+		return store.NewClient(client, commits), nil, func() { commits.Close() }, nil
+	}
+}
+
+func workspaceOptions() ([]app.Option, error) {
+	path, err := workspace.DefaultPath()
+	if err != nil {
+		if workspaceName != "" {
+			return nil, fmt.Errorf("workspaces: %w", err)
+		}
+		return nil, nil
+	}
+	workspaces := workspace.New(path)
+	list, err := workspaces.List()
+	if err != nil {
+		return nil, fmt.Errorf("workspaces: %w", err)
+	}
+
+	opts := []app.Option{app.WithWorkspaces(list, workspaces.Save)}
+	if workspaceName != "" {
+		w, err := workspaces.Get(workspaceName)
+		if err != nil {
+			return nil, fmt.Errorf("--workspace: %w", err)
+		}
+		opts = append(opts, app.WithWorkspace(w))
+	}
+	return opts, nil
+}
+
+func newClient(cfg config.Config) (forge.Provider, error) {
+	hosts, explicit := githubHosts(cfg)
+	if len(hosts) == 1 && github.IsDefaultHost(hosts[0]) && len(gitlabURLs) == 0 && len(giteaURLs) == 0 {
+		return newGitHubClient(hosts[0])
+	}
+
+	router := forge.NewRouter()
+	for _, host := range hosts {
+		gh, err := newGitHubClient(host)
+		if err != nil {
+			if !explicit {
+				continue
+			}
+			return nil, fmt.Errorf("%s: %w", host, err)
+		}
+		router.Register(routeHost(host), gh)
+	}
+	for _, u := range gitlabURLs {
+		host, baseURL, err := forgeURL(u, "api/v4")
+		if err != nil {
+			return nil, err
+		}
+		router.Register(host, gitlab.New(baseURL, os.Getenv("GITLAB_TOKEN")))
+	}
+	for _, u := range giteaURLs {
+		host, baseURL, err := forgeURL(u, "api/v1")
+		if err != nil {
+			return nil, err
+		}
+		router.Register(host, gitea.New(baseURL, os.Getenv("GITEA_TOKEN")))
+	}
+	return router, nil
+}
+
+func loadConfig() (string, config.Config, error) {
+	path, err := config.DefaultPath()
+	if err != nil {
+		return "", config.Config{}, nil
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return "", cfg, fmt.Errorf("config: %w", err)
+	}
+	return path, cfg, nil
+}
+
+func githubHosts(cfg config.Config) ([]string, bool) {
+	if len(hostnames) > 0 {
+		return hostnames, true
+	}
+	if len(cfg.Hosts) > 0 {
+		return cfg.Hosts, true
+	}
+	return []string{github.DefaultHost}, false
+}
+
+func routeHost(hostname string) string {
+	if github.IsDefaultHost(hostname) {
+		return ""
+	}
+	return strings.ToLower(hostname)
+}
+
+func newGitHubClient(hostname string) (forge.Provider, error) {
+	opts, err := clientOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	if token := github.Token(hostname); token != "" {
+		return github.NewRESTClient(github.BaseURL(hostname), token, opts...), nil
+	}
+	if err := github.CheckGHInstalled(); err != nil {
+		if !github.IsDefaultHost(hostname) {
+			return nil, fmt.Errorf("gh CLI or GH_ENTERPRISE_TOKEN is required: %w", err)
+		}
+		return nil, fmt.Errorf("gh CLI or GH_TOKEN is required: %w", err)
+	}
+	if err := github.CheckGHAuthenticated(hostname); err != nil {
+		return nil, fmt.Errorf("gh CLI not authenticated, run 'gh auth login --hostname %s': %w", hostname, err)
+	}
+	return github.NewGHClient(hostname, opts...), nil
+}
+
+func forgeURL(raw, apiPath string) (host, baseURL string, err error) {
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", "", fmt.Errorf("invalid forge host %q", raw)
+	}
+	return u.Host, strings.TrimSuffix(u.String(), "/") + "/" + apiPath, nil
+}
+
+func clientOptions() ([]github.Option, error) {
+	var opts []github.Option
+	if len(sources) > 0 {
+		parsed := make([]models.RepoSource, len(sources))
+		for i, s := range sources {
+			source, err := models.ParseRepoSource(s)
+			if err != nil {
+				return nil, err
+			}
+			parsed[i] = source
+		}
+		opts = append(opts, github.WithSources(parsed...))
+	}
+	if len(orgs) > 0 {
+		opts = append(opts, github.WithOrgs(orgs...))
+	}
+
+	if noCache {
+		return opts, nil
+	}
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return nil, fmt.Errorf("cache directory: %w", err)
+	}
+	return append(opts, github.WithCache(cache.New(filepath.Join(dir, "http")), cacheTTL)), nil
+}
+
+func openStore() (*store.Store, error) {
+	path, err := store.DefaultPath()
+	if err != nil {
+		return nil, fmt.Errorf("commit store: %w", err)
+	}
+	s, err := store.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("commit store %s (use --no-cache to skip): %w", path, err)
+	}
+	return s, nil
+}
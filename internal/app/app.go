@@ -1,74 +1,206 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 
-	"github.com/tkozakas/gh-log/internal/github"
+	"github.com/tkozakas/gh-log/internal/forge"
 	"github.com/tkozakas/gh-log/internal/models"
 	"github.com/tkozakas/gh-log/internal/search"
 	"github.com/tkozakas/gh-log/internal/tui"
+	"github.com/tkozakas/gh-log/internal/tui/branchselect"
 	"github.com/tkozakas/gh-log/internal/tui/commitview"
 	"github.com/tkozakas/gh-log/internal/tui/filterform"
 	"github.com/tkozakas/gh-log/internal/tui/reposelect"
+	"github.com/tkozakas/gh-log/internal/tui/workspaceselect"
+	"github.com/tkozakas/gh-log/internal/workspace"
 )
 
 type state int
 
 const (
 	stateLoading state = iota
+	stateWorkspaceSelect
 	stateRepoSelect
 	stateLoadingBranches
 	stateFilterForm
+	stateBranchSelect
 	stateLoadingCommits
 	stateCommitView
+	stateLoadingComparison
+	stateCompareView
 	stateError
 )
 
 type Model struct {
+	ctx           context.Context
+	cancel        context.CancelFunc
+	loadCtx       context.Context
+	loadID        int
+	stream        <-chan tea.Msg
+	pending       int
+	client        forge.Provider
+	savedRepos    []string
+	saveRepos     func([]string) error
+	workspaces    []workspace.Workspace
+	saveWorkspace func(workspace.Workspace) error
+	startup       *workspace.Workspace
+	startCompare  *compareRequest
+	offline       bool
+	cachedAt      time.Time
 	state         state
 	width         int
 	height        int
 	spinner       spinner.Model
 	err           error
+	errRetry      tea.Cmd
+	errPrevState  state
 	repos         []models.Repository
 	selectedRepos []models.Repository
 	repoBranches  []filterform.RepoBranches
+	branchWalk    int
 	filters       models.FilterOptions
-	branches      map[string]string
+	branches      map[string][]string
 	repoCommits   []models.RepoCommits
+	workspaceList workspaceselect.Model
 	repoSelect    reposelect.Model
 	filterForm    filterform.Model
+	branchSelect  branchselect.Model
 	commitView    commitview.Model
+	compareView   commitview.Model
 }
 
-type reposLoadedMsg struct{ repos []models.Repository }
-type allBranchesLoadedMsg struct{ repoBranches []filterform.RepoBranches }
-type commitsLoadedMsg struct{ repoCommits []models.RepoCommits }
+type compareRequest struct {
+	repoName string
+	base     string
+	head     string
+}
+
+type reposLoadedMsg struct {
+	repos      []models.Repository
+	warning    error
+	unresolved error
+}
+type savedRepoMsg struct {
+	id   string
+	repo models.Repository
+	err  error
+}
+type branchesLoadedMsg struct {
+	loadID       int
+	index        int
+	repoBranches filterform.RepoBranches
+}
+type commitsLoadedMsg struct {
+	loadID      int
+	repoCommits []models.RepoCommits
+}
 type moreCommitsLoadedMsg struct {
 	repoName string
-	commits  []models.Commit
-	page     int
-	hasMore  bool
+	more     models.RepoCommits
+	err      error
+}
+type commitDetailLoadedMsg struct {
+	loadID   int
+	repoName string
+	sha      string
+	commit   models.Commit
+	err      error
+}
+type comparisonLoadedMsg struct {
+	loadID     int
+	repo       models.Repository
+	spec       string
+	comparison models.Comparison
+	err        error
+}
+type errMsg struct {
+	err   error
+	retry tea.Cmd
+}
+
+type Option func(*Model)
+
+func WithOffline(cachedAt time.Time) Option {
+	return func(m *Model) {
+		m.offline = true
+		m.cachedAt = cachedAt
+	}
+}
+
+func WithSavedRepos(ids []string, save func([]string) error) Option {
+	return func(m *Model) {
+		m.savedRepos = ids
+		m.saveRepos = save
+	}
 }
-type errMsg struct{ err error }
 
-func New() Model {
+func WithWorkspaces(workspaces []workspace.Workspace, save func(workspace.Workspace) error) Option {
+	return func(m *Model) {
+		m.workspaces = workspaces
+		m.saveWorkspace = save
+	}
+}
+
+func WithWorkspace(w workspace.Workspace) Option {
+	return func(m *Model) {
+		m.startup = &w
+	}
+}
+
+func WithCompare(repoName, base, head string) Option {
+	return func(m *Model) {
+		m.startCompare = &compareRequest{repoName: repoName, base: base, head: head}
+	}
+}
+
+func New(ctx context.Context, client forge.Provider, opts ...Option) Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = tui.SelectedStyle
 
-	return Model{
+	m := Model{
+		ctx:      ctx,
+		loadCtx:  ctx,
+		client:   client,
 		state:    stateLoading,
 		spinner:  s,
-		branches: make(map[string]string),
+		branches: make(map[string][]string),
 	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	switch {
+	case m.startCompare != nil:
+		m.state = stateLoadingComparison
+	case m.startup == nil && len(m.workspaces) > 0:
+		m.workspaceList = workspaceselect.New(m.workspaces, m.width, m.height)
+		m.state = stateWorkspaceSelect
+	}
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(m.spinner.Tick, loadRepos)
+	switch {
+	case m.startCompare != nil:
+		return tea.Batch(m.spinner.Tick, m.loadStartupComparison)
+	case m.startup != nil:
+		w := *m.startup
+		return tea.Batch(m.spinner.Tick, func() tea.Msg { return workspaceselect.DoneMsg{Workspace: w} })
+	case m.state == stateWorkspaceSelect:
+		return m.spinner.Tick
+	default:
+		return tea.Batch(m.spinner.Tick, m.loadRepos)
+	}
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -80,8 +212,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case tea.KeyMsg:
 		if msg.String() == "ctrl+c" {
+			m.cancelLoad()
 			return m, tea.Quit
 		}
+		if m.state == stateError {
+			return m.updateError(msg)
+		}
 
 	case spinner.TickMsg:
 		var cmd tea.Cmd
@@ -90,18 +226,70 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case reposLoadedMsg:
 		m.repos = msg.repos
-		m.repoSelect = reposelect.New(m.repos, m.width, m.height)
+		m.repoSelect = m.newRepoSelect()
+		var notices []string
+		if msg.warning != nil {
+			notices = append(notices, "some hosts failed: "+msg.warning.Error())
+		}
+		if msg.unresolved != nil {
+			notices = append(notices, "some saved repos could not be loaded: "+msg.unresolved.Error())
+		}
+		if len(notices) > 0 {
+			m.repoSelect.SetNotice(strings.Join(notices, " • "))
+		}
 		m.state = stateRepoSelect
 		return m, nil
 
+	case workspaceselect.DoneMsg:
+		return m.openWorkspace(msg.Workspace)
+
+	case workspaceselect.NewSelectionMsg:
+		m.state = stateLoading
+		return m, m.loadRepos
+
+	case reposelect.LookupMsg:
+		return m, m.lookupRepos(msg)
+
+	case reposelect.AddedMsg:
+		return m.rememberRepo(msg.Repo), nil
+
 	case reposelect.DoneMsg:
 		m.selectedRepos = msg.Selected
-		m.state = stateLoadingBranches
-		return m, m.loadAllBranches()
+		return m.loadAllBranches()
 
-	case allBranchesLoadedMsg:
-		m.repoBranches = msg.repoBranches
-		m.filterForm = filterform.New(m.repoBranches)
+	case branchesLoadedMsg:
+		if msg.loadID != m.loadID {
+			return m, nil
+		}
+		m.repoBranches[msg.index] = msg.repoBranches
+		m.pending--
+		return m, waitForStream(m.loadID, m.stream)
+
+	case commitsLoadedMsg:
+		if msg.loadID != m.loadID {
+			return m, nil
+		}
+		m.repoCommits = mergeRepoCommits(m.repoCommits, msg.repoCommits)
+		m.pending -= len(msg.repoCommits)
+		return m.showCommits(), waitForStream(m.loadID, m.stream)
+
+	case streamDoneMsg:
+		if msg.loadID != m.loadID {
+			return m, nil
+		}
+		return m.finishLoad()
+
+	case filterform.PickBranchMsg:
+		return m.pickBranch(msg.Index), nil
+
+	case branchselect.DoneMsg:
+		m.filterForm.SetBranches(msg.Repo.ID(), msg.Branches)
+		return m.pickBranch(m.branchWalk + 1), nil
+
+	case branchselect.UseDefaultMsg:
+		for _, rb := range m.repoBranches[m.branchWalk:] {
+			m.filterForm.SetBranches(rb.Repo.ID(), []string{rb.Repo.DefaultBranchName})
+		}
 		m.state = stateFilterForm
 		return m, nil
 
@@ -110,25 +298,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.branches = msg.Branches
 		return m.loadAllCommits()
 
-	case commitsLoadedMsg:
-		m.repoCommits = msg.repoCommits
-		m.commitView = commitview.New(m.repoCommits, m.width, m.height)
-		m.state = stateCommitView
-		return m, nil
-
 	case moreCommitsLoadedMsg:
 		m.repoCommits = updateRepoCommits(m.repoCommits, msg)
 		m.commitView.UpdateCommits(m.repoCommits)
+		m.commitView.SetRateLimit(m.client.RateLimit())
 		return m, nil
 
 	case commitview.LoadMoreMsg:
-		return m, m.loadMoreCommits(msg.RepoName, msg.NextPage)
+		return m, m.loadMoreCommits(msg)
+
+	case commitview.RetryMsg:
+		return m.retry(msg.RepoNames)
 
 	case commitview.RestartMsg:
 		return m.restart()
 
+	case commitview.SaveWorkspaceMsg:
+		return m.saveCurrentWorkspace(msg.Name), nil
+
+	case commitview.LoadDetailMsg:
+		return m, m.loadCommitDetail(msg)
+
+	case commitDetailLoadedMsg:
+		if msg.loadID != m.loadID {
+			return m, nil
+		}
+		return m.showCommitDetail(msg), nil
+
+	case commitview.CompareMsg:
+		return m, m.compare(msg.Repo, msg.Base, msg.Head)
+
+	case comparisonLoadedMsg:
+		if msg.loadID != m.loadID {
+			return m, nil
+		}
+		return m.showComparison(msg), nil
+
+	case commitview.BackMsg:
+		if m.state == stateCompareView && m.repoCommits != nil {
+			m.commitView.SetStatus("")
+			m.state = stateCommitView
+			return m.propagateSize(), nil
+		}
+		return m, nil
+
 	case errMsg:
 		m.err = msg.err
+		m.errRetry = msg.retry
+		m.errPrevState = m.state
 		m.state = stateError
 		return m, nil
 	}
@@ -140,16 +357,24 @@ func (m Model) View() string {
 	switch m.state {
 	case stateLoading:
 		return m.viewLoading("Loading repositories...")
+	case stateWorkspaceSelect:
+		return m.workspaceList.View()
 	case stateRepoSelect:
 		return m.repoSelect.View()
 	case stateLoadingBranches:
-		return m.viewLoading("Loading branches...")
+		return m.viewLoading(m.progress("Loading branches"))
 	case stateFilterForm:
 		return m.filterForm.View()
+	case stateBranchSelect:
+		return m.branchSelect.View()
 	case stateLoadingCommits:
-		return m.viewLoading("Loading commits...")
+		return m.viewLoading(m.progress("Loading commits"))
 	case stateCommitView:
 		return m.commitView.View()
+	case stateLoadingComparison:
+		return m.viewLoading(fmt.Sprintf("Comparing %s...%s...", m.startCompare.base, m.startCompare.head))
+	case stateCompareView:
+		return m.compareView.View()
 	case stateError:
 		return m.viewError()
 	default:
@@ -161,12 +386,18 @@ func (m Model) updateChild(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch m.state {
+	case stateWorkspaceSelect:
+		m.workspaceList, cmd = m.workspaceList.Update(msg)
 	case stateRepoSelect:
 		m.repoSelect, cmd = m.repoSelect.Update(msg)
 	case stateFilterForm:
 		m.filterForm, cmd = m.filterForm.Update(msg)
+	case stateBranchSelect:
+		m.branchSelect, cmd = m.branchSelect.Update(msg)
 	case stateCommitView:
 		m.commitView, cmd = m.commitView.Update(msg)
+	case stateCompareView:
+		m.compareView, cmd = m.compareView.Update(msg)
 	}
 
 	return m, cmd
@@ -174,133 +405,660 @@ func (m Model) updateChild(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 func (m Model) propagateSize() Model {
 	switch m.state {
+	case stateWorkspaceSelect:
+		m.workspaceList, _ = m.workspaceList.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
 	case stateRepoSelect:
 		m.repoSelect, _ = m.repoSelect.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	case stateBranchSelect:
+		m.branchSelect, _ = m.branchSelect.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
 	case stateCommitView:
 		m.commitView, _ = m.commitView.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	case stateCompareView:
+		m.compareView, _ = m.compareView.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
 	}
 	return m
 }
 
-func (m Model) loadAllBranches() tea.Cmd {
-	return func() tea.Msg {
-		var repoBranches []filterform.RepoBranches
+func (m Model) startLoad(jobs int) Model {
+	m.cancelLoad()
+	m.loadCtx, m.cancel = context.WithCancel(m.ctx)
+	m.loadID++
+	m.pending = jobs
+	return m
+}
 
-		for _, repo := range m.selectedRepos {
-			branches, err := github.ListBranches(repo.Owner(), repo.RepoName())
-			if err != nil {
-				return errMsg{err: err}
-			}
-			repoBranches = append(repoBranches, filterform.RepoBranches{
-				Repo:     repo,
-				Branches: branches,
-			})
+func (m *Model) cancelLoad() {
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
+func (m Model) finishLoad() (Model, tea.Cmd) {
+	switch m.state {
+	case stateLoadingBranches:
+		m.filterForm = filterform.New(m.repoBranches)
+		m.state = stateFilterForm
+	case stateLoadingCommits:
+		m = m.showCommits()
+	}
+	m.stream = nil
+	return m, nil
+}
+
+func (m Model) newRepoSelect() reposelect.Model {
+	rs := reposelect.New(m.repos, m.width, m.height)
+	if m.offline {
+		rs.SetNotice("offline • repositories cached " + models.Age(m.cachedAt))
+	}
+	return rs
+}
+
+func (m Model) showCommits() Model {
+	if m.state == stateLoadingCommits {
+		m.commitView = commitview.New(m.repoCommits, m.width, m.height)
+		m.commitView.SetOffline(m.offline)
+		m.state = stateCommitView
+	} else {
+		m.commitView.UpdateCommits(m.repoCommits)
+	}
+	m.commitView.SetRateLimit(m.client.RateLimit())
+	return m
+}
+
+func (m Model) loadAllBranches() (Model, tea.Cmd) {
+	m = m.startLoad(len(m.selectedRepos))
+	m.state = stateLoadingBranches
+	m.repoBranches = make([]filterform.RepoBranches, len(m.selectedRepos))
+
+	loadID := m.loadID
+	indexes := make([]int, len(m.selectedRepos))
+	for i := range indexes {
+		indexes[i] = i
+	}
+
+	m.stream = runPool(m.loadCtx, indexes, func(ctx context.Context, i int) tea.Msg {
+		repo := m.selectedRepos[i]
+		branches, err := m.client.ListBranches(ctx, repo)
+		return branchesLoadedMsg{
+			loadID:       loadID,
+			index:        i,
+			repoBranches: filterform.RepoBranches{Repo: repo, Branches: branches, Err: err},
 		}
+	})
+	return m, waitForStream(loadID, m.stream)
+}
 
-		return allBranchesLoadedMsg{repoBranches: repoBranches}
+func (m Model) pickBranch(index int) Model {
+	for index >= 0 && index < len(m.repoBranches) && m.repoBranches[index].Err != nil {
+		index++
+	}
+	if index < 0 || index >= len(m.repoBranches) {
+		m.state = stateFilterForm
+		return m
 	}
+	rb := m.repoBranches[index]
+	m.branchWalk = index
+	m.branchSelect = branchselect.New(rb.Repo, rb.Branches, m.width, m.height)
+	m.state = stateBranchSelect
+	return m
 }
 
 func (m Model) loadAllCommits() (Model, tea.Cmd) {
 	m.state = stateLoadingCommits
-	return m, m.loadCommitsCmd()
+	m.repoCommits = make([]models.RepoCommits, len(m.selectedRepos))
+	for i, repo := range m.selectedRepos {
+		m.repoCommits[i] = m.emptyRepoCommits(repo)
+	}
+	return m.loadCommits(m.selectedRepos)
 }
 
-func (m Model) loadCommitsCmd() tea.Cmd {
-	return func() tea.Msg {
-		var repoCommits []models.RepoCommits
-		for _, repo := range m.selectedRepos {
-			branch := m.branches[repo.NameWithOwner]
-			if branch == "" {
-				branch = repo.DefaultBranchName
-			}
+func (m Model) loadCommits(repos []models.Repository) (Model, tea.Cmd) {
+	m = m.startLoad(len(repos))
 
-			commits, hasMore, err := github.GetCommits(repo.Owner(), repo.RepoName(), branch, m.filters, 1)
-			if err != nil {
-				return errMsg{err: err}
-			}
+	groups := make([][]forge.HistoryRef, len(repos))
+	loading := make([]models.RepoCommits, len(repos))
+	for i, repo := range repos {
+		for _, branch := range m.branchesFor(repo) {
+			groups[i] = append(groups[i], forge.HistoryRef{Repository: repo, Branch: branch})
+		}
+		loading[i] = m.emptyRepoCommits(repo)
+		loading[i].Status = models.RepoStatusLoading
+	}
+	m.repoCommits = mergeRepoCommits(m.repoCommits, loading)
 
-			commits, err = applySemanticFilter(commits, m.filters.SemanticQuery)
-			if err != nil {
-				return errMsg{err: err}
-			}
+	loadID := m.loadID
+	if m.filters.AllBranches {
+		m.stream = runPool(m.loadCtx, repos, func(ctx context.Context, repo models.Repository) tea.Msg {
+			return commitsLoadedMsg{loadID: loadID, repoCommits: []models.RepoCommits{m.fetchAllBranches(ctx, repo)}}
+		})
+		return m, waitForStream(loadID, m.stream)
+	}
+	m.stream = runPool(m.loadCtx, chunk(groups, maxWorkers), func(ctx context.Context, batch [][]forge.HistoryRef) tea.Msg {
+		return commitsLoadedMsg{loadID: loadID, repoCommits: m.fetchRepoHistories(ctx, batch)}
+	})
+	return m, waitForStream(loadID, m.stream)
+}
+
+func (m Model) fetchRepoHistories(ctx context.Context, groups [][]forge.HistoryRef) []models.RepoCommits {
+	var refs []forge.HistoryRef
+	for _, group := range groups {
+		refs = append(refs, group...)
+	}
+	histories := m.fetchHistories(ctx, refs)
+
+	repoCommits := make([]models.RepoCommits, len(groups))
+	for i, group := range groups {
+		batch := histories[:len(group)]
+		histories = histories[len(group):]
+		if len(batch) == 1 {
+			repoCommits[i] = batch[0]
+			continue
+		}
+		repoCommits[i] = forge.MergeHistories(batch)
+	}
+	return repoCommits
+}
 
-			repoCommits = append(repoCommits, models.RepoCommits{
-				Repository: repo,
-				Branch:     branch,
-				Commits:    commits,
-				Page:       1,
-				HasMore:    hasMore,
-			})
+func (m Model) fetchAllBranches(ctx context.Context, repo models.Repository) models.RepoCommits {
+	rc := models.RepoCommits{Repository: repo, Branch: repo.DefaultBranchName}
+	refs, err := forge.BranchRefs(ctx, m.client, repo, m.filters)
+	switch {
+	case err != nil:
+		rc = forge.FailedHistory(forge.HistoryRef{Repository: repo, Branch: repo.DefaultBranchName}, err)
+	case len(refs) > 0:
+		histories := m.fetchHistories(ctx, refs)
+		rc = forge.MergeHistories(histories)
+		unmerged, err := forge.UnmergedCommits(ctx, m.client, repo, histories)
+		rc.Unmerged = unmerged
+		if err != nil {
+			rc.Status = models.RepoStatusFailed
+			rc.Err = errors.Join(rc.Err, err)
 		}
+	}
+	rc.AllBranches = true
+	return rc
+}
 
-		return commitsLoadedMsg{repoCommits: repoCommits}
+func (m Model) fetchHistories(ctx context.Context, refs []forge.HistoryRef) []models.RepoCommits {
+	repoCommits, err := m.client.GetHistories(ctx, refs, m.filters)
+	if err != nil {
+		return failedRepoCommits(refs, err)
 	}
+
+	for i, rc := range repoCommits {
+		if errors.Is(rc.Err, forge.ErrEmptyRepository) {
+			repoCommits[i].Status = models.RepoStatusLoaded
+			repoCommits[i].Err = nil
+			continue
+		}
+		if rc.Failed() {
+			continue
+		}
+		commits, err := applySemanticFilter(rc.Commits, m.filters.SemanticQuery)
+		if err != nil {
+			repoCommits[i].Status = models.RepoStatusFailed
+			repoCommits[i].Err = err
+			continue
+		}
+		repoCommits[i].Commits = commits
+		if m.filters.HasSemanticFilter() {
+			repoCommits[i].TotalCount = 0
+		}
+	}
+	return repoCommits
 }
 
-func (m Model) loadMoreCommits(repoName string, page int) tea.Cmd {
+func (m Model) retry(repoNames []string) (Model, tea.Cmd) {
+	if m.pending > 0 {
+		return m, nil
+	}
+
+	var reload []models.Repository
+	var cmds []tea.Cmd
+	for _, name := range repoNames {
+		rc, ok := findRepoCommits(m.repoCommits, name)
+		if !ok {
+			continue
+		}
+		if len(rc.Commits) > 0 && !rc.MultiBranch() {
+			cmds = append(cmds, m.loadMoreCommits(commitview.LoadMoreMsg{
+				RepoName: name,
+				NextPage: rc.NextPage,
+				Cursor:   rc.Cursor,
+			}))
+			continue
+		}
+		reload = append(reload, rc.Repository)
+	}
+
+	if len(reload) > 0 {
+		var cmd tea.Cmd
+		m, cmd = m.loadCommits(reload)
+		m = m.showCommits()
+		cmds = append(cmds, cmd)
+	}
+	return m, tea.Batch(cmds...)
+}
+
+func (m Model) loadMoreCommits(msg commitview.LoadMoreMsg) tea.Cmd {
+	ctx := m.loadCtx
 	return func() tea.Msg {
+		if rc, ok := findRepoCommits(m.repoCommits, msg.RepoName); ok && rc.MultiBranch() {
+			return m.loadMoreBranches(ctx, rc)
+		}
+
 		for _, repo := range m.selectedRepos {
-			if repo.NameWithOwner != repoName {
+			if repo.ID() != msg.RepoName {
 				continue
 			}
 
-			branch := m.branches[repo.NameWithOwner]
-			if branch == "" {
-				branch = repo.DefaultBranchName
+			branch := m.branchesFor(repo)[0]
+			if msg.Cursor != "" {
+				return m.loadMoreByCursor(ctx, repo, branch, msg.Cursor)
 			}
 
-			commits, hasMore, err := github.GetCommits(repo.Owner(), repo.RepoName(), branch, m.filters, page)
+			result, err := m.client.GetCommits(ctx, repo, branch, m.filters, msg.NextPage)
+			if ctx.Err() != nil {
+				return nil
+			}
 			if err != nil {
-				return errMsg{err: err}
+				return moreCommitsLoadedMsg{repoName: repo.ID(), err: err}
 			}
 
 			return moreCommitsLoadedMsg{
-				repoName: repoName,
-				commits:  commits,
-				page:     page,
-				hasMore:  hasMore,
+				repoName: repo.ID(),
+				more: models.RepoCommits{
+					Commits:    result.Commits,
+					Page:       result.Page,
+					NextPage:   result.NextPage,
+					HasMore:    result.HasMore(),
+					TotalCount: result.TotalCount,
+				},
 			}
 		}
 		return nil
 	}
 }
 
+func (m Model) loadMoreByCursor(ctx context.Context, repo models.Repository, branch, cursor string) tea.Msg {
+	ref := forge.HistoryRef{Repository: repo, Branch: branch, Cursor: cursor}
+	results, err := m.client.GetHistories(ctx, []forge.HistoryRef{ref}, m.filters)
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err == nil && len(results) == 0 {
+		return nil
+	}
+	if err == nil && results[0].Failed() {
+		err = results[0].Err
+	}
+	if err != nil {
+		return moreCommitsLoadedMsg{repoName: repo.ID(), err: err}
+	}
+	return moreCommitsLoadedMsg{repoName: repo.ID(), more: results[0]}
+}
+
+func (m Model) loadMoreBranches(ctx context.Context, rc models.RepoCommits) tea.Msg {
+	var refs []forge.HistoryRef
+	for _, branch := range rc.Branches {
+		if cursor, ok := rc.Cursors[branch]; ok {
+			refs = append(refs, forge.HistoryRef{Repository: rc.Repository, Branch: branch, Cursor: cursor})
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	more := forge.MergeHistories(m.fetchHistories(ctx, refs))
+	if ctx.Err() != nil {
+		return nil
+	}
+	if more.Failed() {
+		return moreCommitsLoadedMsg{repoName: rc.Repository.ID(), err: more.Err}
+	}
+	return moreCommitsLoadedMsg{repoName: rc.Repository.ID(), more: more}
+}
+
+func (m Model) branchesFor(repo models.Repository) []string {
+	if branches := m.branches[repo.ID()]; len(branches) > 0 {
+		return branches
+	}
+	return []string{repo.DefaultBranchName}
+}
+
+func (m Model) emptyRepoCommits(repo models.Repository) models.RepoCommits {
+	branches := m.branchesFor(repo)
+	if m.filters.AllBranches {
+		return models.RepoCommits{Repository: repo, Branch: repo.DefaultBranchName, AllBranches: true}
+	}
+	rc := models.RepoCommits{Repository: repo, Branch: branches[0]}
+	if len(branches) > 1 {
+		rc.Branches = branches
+	}
+	return rc
+}
+
 func (m Model) restart() (Model, tea.Cmd) {
+	m.cancelLoad()
+	m.loadID++
+	m.stream = nil
 	m.selectedRepos = nil
-	m.branches = make(map[string]string)
+	m.branches = make(map[string][]string)
 	m.repoCommits = nil
-	m.repoSelect = reposelect.New(m.repos, m.width, m.height)
+	if m.repos == nil {
+		m.state = stateLoading
+		return m, m.loadRepos
+	}
+	m.repoSelect = m.newRepoSelect()
 	m.state = stateRepoSelect
 	return m, nil
 }
 
+func (m Model) openWorkspace(w workspace.Workspace) (Model, tea.Cmd) {
+	m.selectedRepos = w.Repos
+	m.branches = maps.Clone(w.Branches)
+	if m.branches == nil {
+		m.branches = make(map[string][]string)
+	}
+	m.filters = w.Filters
+	m.filters.Validate()
+	return m.loadAllCommits()
+}
+
+func (m Model) saveCurrentWorkspace(name string) Model {
+	if m.saveWorkspace == nil {
+		m.commitView.SetStatus("workspaces are not available in this mode")
+		return m
+	}
+
+	branches := make(map[string][]string)
+	for _, repo := range m.selectedRepos {
+		if selected := m.branchesFor(repo); !slices.Equal(selected, []string{repo.DefaultBranchName}) {
+			branches[repo.ID()] = selected
+		}
+	}
+	w := workspace.Workspace{Name: name, Repos: m.selectedRepos, Branches: branches, Filters: m.filters}
+	if err := m.saveWorkspace(w); err != nil {
+		m.commitView.SetStatus("could not save workspace " + name + ": " + err.Error())
+		return m
+	}
+	m.commitView.SetStatus(fmt.Sprintf("saved workspace %s (%d repos)", name, len(w.Repos)))
+	return m
+}
+
+func (m Model) loadCommitDetail(msg commitview.LoadDetailMsg) tea.Cmd {
+	ctx, loadID := m.loadCtx, m.loadID
+	return func() tea.Msg {
+		commit, err := m.client.GetCommit(ctx, msg.Repo, msg.SHA)
+		return commitDetailLoadedMsg{loadID: loadID, repoName: msg.Repo.ID(), sha: msg.SHA, commit: commit, err: err}
+	}
+}
+
+func (m Model) showCommitDetail(msg commitDetailLoadedMsg) Model {
+	if m.repoCommits != nil {
+		if msg.err == nil {
+			m.repoCommits = models.SetCommitDetail(m.repoCommits, msg.repoName, msg.commit)
+		}
+		m.commitView.SetDetail(msg.repoName, msg.sha, msg.commit, msg.err)
+		m.commitView.SetRateLimit(m.client.RateLimit())
+	}
+	if m.state == stateCompareView {
+		m.compareView.SetDetail(msg.repoName, msg.sha, msg.commit, msg.err)
+		m.compareView.SetRateLimit(m.client.RateLimit())
+	}
+	return m
+}
+
+func (m Model) compare(repo models.Repository, base, head string) tea.Cmd {
+	ctx, loadID := m.loadCtx, m.loadID
+	return func() tea.Msg {
+		comparison, err := m.client.Compare(ctx, repo, base, head)
+		return comparisonLoadedMsg{loadID: loadID, repo: repo, spec: base + "..." + head, comparison: comparison, err: err}
+	}
+}
+
+func (m Model) loadStartupComparison() tea.Msg {
+	req := m.startCompare
+	repo, err := m.client.GetRepository(m.ctx, req.repoName)
+	if err != nil {
+		return errMsg{err: err, retry: m.loadStartupComparison}
+	}
+	comparison, err := m.client.Compare(m.ctx, repo, req.base, req.head)
+	if err != nil {
+		return errMsg{err: err, retry: m.loadStartupComparison}
+	}
+	return comparisonLoadedMsg{loadID: m.loadID, repo: repo, comparison: comparison}
+}
+
+func (m Model) showComparison(msg comparisonLoadedMsg) Model {
+	if m.state != stateCommitView && m.state != stateLoadingComparison {
+		return m
+	}
+	if msg.err != nil {
+		m.commitView.SetStatus(fmt.Sprintf("could not compare %s: %v", msg.spec, msg.err))
+		return m
+	}
+	m.compareView = commitview.NewComparison(msg.repo, msg.comparison, m.width, m.height)
+	m.compareView.SetOffline(m.offline)
+	m.compareView.SetStandalone(m.repoCommits == nil)
+	m.compareView.SetRateLimit(m.client.RateLimit())
+	m.state = stateCompareView
+	return m
+}
+
+func (m Model) progress(label string) string {
+	total := len(m.selectedRepos)
+	if total == 0 {
+		return label + "..."
+	}
+	return fmt.Sprintf("%s... (%d/%d)", label, total-m.pending, total)
+}
+
 func (m Model) viewLoading(msg string) string {
 	return fmt.Sprintf("\n  %s %s\n", m.spinner.View(), msg)
 }
 
+func (m Model) updateError(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, tui.Keys.Retry):
+		if m.errRetry != nil {
+			m.state = m.errPrevState
+			return m, m.errRetry
+		}
+	case key.Matches(msg, tui.Keys.Back):
+		if m.canGoBack() {
+			m.state = m.errPrevState
+			return m.propagateSize(), nil
+		}
+	case key.Matches(msg, tui.Keys.Restart):
+		return m.restart()
+	case key.Matches(msg, tui.Keys.Quit):
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m Model) canGoBack() bool {
+	switch m.errPrevState {
+	case stateRepoSelect, stateFilterForm, stateBranchSelect, stateCommitView, stateCompareView:
+		return true
+	default:
+		return false
+	}
+}
+
 func (m Model) viewError() string {
-	return tui.ErrorStyle.Render(fmt.Sprintf("\n  Error: %v\n\n  Press q to quit.\n", m.err))
+	kind := classifyError(m.err)
+
+	var b strings.Builder
+	b.WriteString(tui.ErrorStyle.Render(fmt.Sprintf("\n  Error (%s): %v", kind, m.err)))
+	b.WriteString("\n")
+	if hint := errorHint(m.err); hint != "" {
+		b.WriteString("\n  " + tui.DimStyle.Render(hint) + "\n")
+	}
+
+	actions := []string{}
+	if m.errRetry != nil {
+		actions = append(actions, "t: retry")
+	}
+	if m.canGoBack() {
+		actions = append(actions, "esc: back")
+	}
+	actions = append(actions, "r: restart", "q: quit")
+	b.WriteString(tui.HelpStyle.Render("  " + strings.Join(actions, " • ")))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func mergeRepoCommits(current, loaded []models.RepoCommits) []models.RepoCommits {
+	repoCommits := append([]models.RepoCommits(nil), current...)
+	for _, l := range loaded {
+		for i, rc := range repoCommits {
+			if rc.Repository.ID() == l.Repository.ID() {
+				repoCommits[i] = l
+				break
+			}
+		}
+	}
+	return repoCommits
+}
+
+func findRepoCommits(repoCommits []models.RepoCommits, repoName string) (models.RepoCommits, bool) {
+	for _, rc := range repoCommits {
+		if rc.Repository.ID() == repoName {
+			return rc, true
+		}
+	}
+	return models.RepoCommits{}, false
+}
+
+func failedRepoCommits(refs []forge.HistoryRef, err error) []models.RepoCommits {
+	repoCommits := make([]models.RepoCommits, len(refs))
+	for i, ref := range refs {
+		repoCommits[i] = models.RepoCommits{
+			Repository: ref.Repository,
+			Branch:     ref.Branch,
+			Status:     models.RepoStatusFailed,
+			Err:        err,
+		}
+	}
+	return repoCommits
 }
 
-func updateRepoCommits(repoCommits []models.RepoCommits, msg moreCommitsLoadedMsg) []models.RepoCommits {
+func updateRepoCommits(current []models.RepoCommits, msg moreCommitsLoadedMsg) []models.RepoCommits {
+	repoCommits := append([]models.RepoCommits(nil), current...)
 	for i, rc := range repoCommits {
-		if rc.Repository.NameWithOwner == msg.repoName {
-			repoCommits[i].Commits = append(rc.Commits, msg.commits...)
-			repoCommits[i].Page = msg.page
-			repoCommits[i].HasMore = msg.hasMore
+		if rc.Repository.ID() == msg.repoName && msg.err != nil {
+			repoCommits[i].Status = models.RepoStatusFailed
+			repoCommits[i].Err = msg.err
+			break
+		}
+		if rc.Repository.ID() == msg.repoName && rc.MultiBranch() {
+			repoCommits[i].Status = models.RepoStatusLoaded
+			repoCommits[i].Err = nil
+			repoCommits[i].Commits = models.MergeCommits(rc.Commits, msg.more.Commits)
+			repoCommits[i].Cursors = msg.more.Cursors
+			repoCommits[i].HasMore = msg.more.HasMore
+			break
+		}
+		if rc.Repository.ID() == msg.repoName {
+			repoCommits[i].Status = models.RepoStatusLoaded
+			repoCommits[i].Err = nil
+			repoCommits[i].Commits = append(append([]models.Commit(nil), rc.Commits...), msg.more.Commits...)
+			repoCommits[i].Page = msg.more.Page
+			repoCommits[i].NextPage = msg.more.NextPage
+			repoCommits[i].Cursor = msg.more.Cursor
+			repoCommits[i].HasMore = msg.more.HasMore
+			if rc.TotalCount > 0 && msg.more.TotalCount > 0 {
+				repoCommits[i].TotalCount = msg.more.TotalCount
+			}
 			break
 		}
 	}
 	return repoCommits
 }
 
-func loadRepos() tea.Msg {
-	repos, err := github.ListRepositories()
-	if err != nil {
-		return errMsg{err: err}
+func (m Model) loadRepos() tea.Msg {
+	repos, err := m.client.ListRepositories(m.ctx)
+	var partial *forge.PartialError
+	if err != nil && !errors.As(err, &partial) {
+		return errMsg{err: err, retry: m.loadRepos}
+	}
+	repos, unresolved := m.addSavedRepos(repos)
+	return reposLoadedMsg{repos: repos, warning: err, unresolved: unresolved}
+}
+
+func (m Model) addSavedRepos(repos []models.Repository) ([]models.Repository, error) {
+	var missing []string
+	for _, id := range m.savedRepos {
+		if !slices.ContainsFunc(repos, func(r models.Repository) bool { return r.ID() == id }) {
+			missing = append(missing, id)
+		}
+	}
+
+	resolved := make(map[string]savedRepoMsg, len(missing))
+	for msg := range runPool(m.ctx, missing, func(ctx context.Context, id string) tea.Msg {
+		repo, err := m.client.GetRepository(ctx, id)
+		return savedRepoMsg{id: id, repo: repo, err: err}
+	}) {
+		saved := msg.(savedRepoMsg)
+		resolved[saved.id] = saved
+	}
+
+	var errs []error
+	for _, id := range m.savedRepos {
+		i := slices.IndexFunc(repos, func(r models.Repository) bool { return r.ID() == id })
+		if i < 0 {
+			saved, ok := resolved[id]
+			if !ok {
+				continue
+			}
+			if saved.err != nil {
+				errs = append(errs, saved.err)
+				continue
+			}
+			repos = append(repos, saved.repo)
+			i = len(repos) - 1
+		}
+		repos[i].Sources = append(slices.Clone(repos[i].Sources), models.SourceSaved)
+	}
+	if len(errs) > 0 {
+		return repos, &forge.PartialError{Errs: errs}
 	}
-	return reposLoadedMsg{repos: repos}
+	return repos, nil
+}
+
+func (m Model) lookupRepos(msg reposelect.LookupMsg) tea.Cmd {
+	ctx := m.ctx
+	return func() tea.Msg {
+		result := reposelect.LookupResultMsg{Query: msg.Query, Exact: msg.Exact}
+		if msg.Exact {
+			repo, err := m.client.GetRepository(ctx, msg.Query)
+			if err == nil {
+				result.Repos = []models.Repository{repo}
+			}
+			result.Err = err
+			return result
+		}
+		result.Repos, result.Err = m.client.SearchRepositories(ctx, msg.Query)
+		return result
+	}
+}
+
+func (m Model) rememberRepo(repo models.Repository) Model {
+	m.repos = append([]models.Repository{repo}, m.repos...)
+	if slices.Contains(m.savedRepos, repo.ID()) {
+		return m
+	}
+	m.savedRepos = append(slices.Clone(m.savedRepos), repo.ID())
+	if m.saveRepos == nil {
+		return m
+	}
+	if err := m.saveRepos(m.savedRepos); err != nil {
+		m.repoSelect.SetNotice("could not remember " + repo.ID() + ": " + err.Error())
+	}
+	return m
 }
 
 func applySemanticFilter(commits []models.Commit, query string) ([]models.Commit, error) {
@@ -0,0 +1,544 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/github"
+	"github.com/tkozakas/gh-log/internal/models"
+	"github.com/tkozakas/gh-log/internal/tui/branchselect"
+	"github.com/tkozakas/gh-log/internal/tui/commitview"
+	"github.com/tkozakas/gh-log/internal/tui/filterform"
+	"github.com/tkozakas/gh-log/internal/tui/reposelect"
+	"github.com/tkozakas/gh-log/internal/tui/workspaceselect"
+	"github.com/tkozakas/gh-log/internal/workspace"
+)
+
+type fakeClient struct {
+	forge.Provider
+	histories  map[string][]models.Commit
+	refs       []forge.HistoryRef
+	branchErrs map[string]error
+	repos      []models.Repository
+	reposErr   error
+}
+
+func (f *fakeClient) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	return f.repos, f.reposErr
+}
+
+func (f *fakeClient) GetHistories(ctx context.Context, refs []forge.HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error) {
+	f.refs = append(f.refs, refs...)
+	results := make([]models.RepoCommits, len(refs))
+	for i, ref := range refs {
+		results[i] = models.RepoCommits{Repository: ref.Repository, Branch: ref.Branch, Commits: f.histories[ref.Branch]}
+		if ref.Cursor == "" && ref.Branch == "release" {
+			results[i].HasMore = true
+			results[i].Cursor = "page2"
+		}
+	}
+	return results, nil
+}
+
+func (f *fakeClient) ListBranches(ctx context.Context, repo models.Repository) ([]models.Branch, error) {
+	if err := f.branchErrs[repo.ID()]; err != nil {
+		return nil, err
+	}
+	var branches []models.Branch
+	for name := range f.histories {
+		branches = append(branches, models.Branch{Name: name})
+	}
+	return branches, nil
+}
+
+func (f *fakeClient) Compare(ctx context.Context, repo models.Repository, base, head string) (models.Comparison, error) {
+	headCommits, ok := f.histories[head]
+	if !ok {
+		return models.Comparison{}, forge.ErrNotFound
+	}
+	var commits []models.Commit
+	for _, c := range headCommits {
+		if !slices.ContainsFunc(f.histories[base], func(b models.Commit) bool { return b.SHA == c.SHA }) {
+			commits = append(commits, c)
+		}
+	}
+	return models.Comparison{Base: base, Head: head, AheadBy: len(commits), Commits: commits}, nil
+}
+
+func (f *fakeClient) GetCommit(ctx context.Context, repo models.Repository, sha string) (models.Commit, error) {
+	if sha == "missing" {
+		return models.Commit{}, forge.ErrNotFound
+	}
+	return models.Commit{
+		SHA:     sha,
+		Parents: []string{"p1"},
+		Files:   []models.FileChange{{Filename: "ledger.go", Status: "modified", Additions: 4, Deletions: 1}},
+		Stats:   &models.CommitStats{Additions: 4, Deletions: 1},
+	}, nil
+}
+
+func (f *fakeClient) RateLimit() models.RateLimit {
+	return models.RateLimit{}
+}
+
+func TestLoadRepos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"name": "repo", "full_name": "owner/repo", "default_branch": "main"}]`))
+	}))
+	defer server.Close()
+
+	m := New(context.Background(), github.NewRESTClient(server.URL, "test-token"))
+
+	result := m.loadRepos()
+	msg, ok := result.(reposLoadedMsg)
+	if !ok {
+		t.Fatalf("loadRepos() returned %T, want reposLoadedMsg", result)
+	}
+	if len(msg.repos) != 1 || msg.repos[0].NameWithOwner != "owner/repo" {
+		t.Errorf("repos = %+v", msg.repos)
+	}
+}
+
+func TestLoadReposWarnsAboutFailedHosts(t *testing.T) {
+	router := forge.NewRouter()
+	router.Register("", &fakeClient{repos: []models.Repository{{NameWithOwner: "acme/api"}}})
+	router.Register("gitlab.example.com", &fakeClient{reposErr: forge.ErrUnauthorized})
+	m := New(context.Background(), router)
+
+	updated, _ := m.Update(m.loadRepos())
+	m = updated.(Model)
+	if m.state != stateRepoSelect || len(m.repos) != 1 {
+		t.Fatalf("state = %v, repos = %+v, want the picker with the working host", m.state, m.repos)
+	}
+	if view := m.repoSelect.View(); !strings.Contains(view, "gitlab.example.com: unauthorized") {
+		t.Error("picker should warn about the failed host")
+	}
+}
+
+func TestLoadReposAddsSavedRepos(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user/repos":
+			w.Write([]byte(`[{"name": "repo", "full_name": "owner/repo", "default_branch": "main"}]`))
+		case "/repos/golang/go":
+			w.Write([]byte(`{"name": "go", "full_name": "golang/go", "default_branch": "master"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := github.NewRESTClient(server.URL, "test-token", github.WithSources(models.SourceOwner))
+	m := New(context.Background(), client, WithSavedRepos([]string{"owner/repo", "golang/go", "gone/away"}, nil))
+
+	msg := m.loadRepos().(reposLoadedMsg)
+	if len(msg.repos) != 2 {
+		t.Fatalf("repos = %+v, want owner/repo and golang/go", msg.repos)
+	}
+	for _, repo := range msg.repos {
+		if !repo.HasSource(models.SourceSaved) {
+			t.Errorf("%s should be marked as saved", repo.NameWithOwner)
+		}
+	}
+
+	updated, _ := m.Update(msg)
+	m = updated.(Model)
+	if view := m.repoSelect.View(); !strings.Contains(view, "could not be loaded: gone/away") {
+		t.Error("picker should warn about the saved repo that could not be loaded")
+	}
+}
+
+func TestRememberRepoSavesOnce(t *testing.T) {
+	var saved [][]string
+	m := New(context.Background(), nil, WithSavedRepos([]string{"owner/repo"}, func(ids []string) error {
+		saved = append(saved, ids)
+		return nil
+	}))
+
+	m = m.rememberRepo(models.Repository{NameWithOwner: "golang/go"})
+	m = m.rememberRepo(models.Repository{NameWithOwner: "owner/repo"})
+
+	if len(saved) != 1 || len(saved[0]) != 2 || saved[0][1] != "golang/go" {
+		t.Errorf("saved = %v, want one save with golang/go appended", saved)
+	}
+	if len(m.repos) != 2 || m.repos[0].NameWithOwner != "owner/repo" {
+		t.Errorf("repos = %+v", m.repos)
+	}
+}
+
+func TestStartupState(t *testing.T) {
+	payments := workspace.Workspace{Name: "payments", Repos: []models.Repository{{NameWithOwner: "acme/ledger"}}}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected state
+	}{
+		{"noWorkspaces", nil, stateLoading},
+		{"picker", []Option{WithWorkspaces([]workspace.Workspace{payments}, nil)}, stateWorkspaceSelect},
+		{"explicit", []Option{WithWorkspaces([]workspace.Workspace{payments}, nil), WithWorkspace(payments)}, stateLoading},
+		{"compare", []Option{WithWorkspaces([]workspace.Workspace{payments}, nil), WithCompare("acme/ledger", "main", "release")}, stateLoadingComparison},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := New(context.Background(), nil, tt.opts...).state; got != tt.expected {
+				t.Errorf("state = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOpenWorkspaceLoadsCommits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	ledger := models.Repository{NameWithOwner: "acme/ledger", DefaultBranchName: "main"}
+	m := New(context.Background(), github.NewRESTClient(server.URL, "test-token"))
+
+	updated, cmd := m.Update(workspaceselect.DoneMsg{Workspace: workspace.Workspace{
+		Name:     "payments",
+		Repos:    []models.Repository{ledger},
+		Branches: map[string][]string{"acme/ledger": {"main", "release"}},
+		Filters:  models.FilterOptions{Author: "dana"},
+	}})
+	m = updated.(Model)
+
+	if m.state != stateLoadingCommits || cmd == nil {
+		t.Fatalf("state = %v, want loading commits", m.state)
+	}
+	if !m.repoCommits[0].MultiBranch() || m.filters.Author != "dana" || m.filters.PerPage != models.DefaultPerPage {
+		t.Errorf("repo commits = %+v, filters = %+v", m.repoCommits[0], m.filters)
+	}
+	m.cancelLoad()
+}
+
+func TestSaveCurrentWorkspace(t *testing.T) {
+	var saved []workspace.Workspace
+	m := New(context.Background(), nil, WithWorkspaces(nil, func(w workspace.Workspace) error {
+		saved = append(saved, w)
+		return nil
+	}))
+	m.selectedRepos = []models.Repository{{NameWithOwner: "acme/ledger"}, {NameWithOwner: "acme/gateway"}}
+	m.selectedRepos[1].DefaultBranchName = "main"
+	m.branches = map[string][]string{"acme/ledger": {"release"}, "acme/gateway": {"main"}, "acme/other": {"dev"}}
+	m.filters = models.FilterOptions{DateFrom: "2024-01-01", PerPage: 50}
+
+	m.saveCurrentWorkspace("payments")
+
+	if len(saved) != 1 {
+		t.Fatalf("saved = %+v, want one workspace", saved)
+	}
+	w := saved[0]
+	if w.Name != "payments" || len(w.Repos) != 2 || len(w.Branches) != 1 || w.Filters.DateFrom != "2024-01-01" {
+		t.Errorf("workspace = %+v", w)
+	}
+}
+
+func TestBranchWalk(t *testing.T) {
+	repoBranches := make([]filterform.RepoBranches, 3)
+	for i, name := range []string{"acme/one", "acme/two", "acme/three"} {
+		repoBranches[i] = filterform.RepoBranches{
+			Repo:     models.Repository{NameWithOwner: name, DefaultBranchName: "main"},
+			Branches: []models.Branch{{Name: "main"}, {Name: "release"}},
+		}
+	}
+	m := New(context.Background(), nil)
+	m.repoBranches = repoBranches
+	m.filterForm = filterform.New(repoBranches)
+	m.state = stateFilterForm
+
+	step := func(msg tea.Msg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(Model)
+	}
+
+	step(filterform.PickBranchMsg{Index: 0})
+	step(branchselect.DoneMsg{Repo: repoBranches[0].Repo, Branches: []string{"release"}})
+	if m.state != stateBranchSelect || m.branchWalk != 1 {
+		t.Fatalf("state = %v, walk = %d, want branch select on the second repo", m.state, m.branchWalk)
+	}
+
+	m.filterForm.SetBranches("acme/three", []string{"release"})
+	step(branchselect.UseDefaultMsg{})
+	if m.state != stateFilterForm {
+		t.Fatalf("state = %v, want filter form", m.state)
+	}
+
+	branches := m.filterForm.Branches()
+	want := map[string]string{"acme/one": "release", "acme/two": "main", "acme/three": "main"}
+	for repo, branch := range want {
+		if len(branches[repo]) != 1 || branches[repo][0] != branch {
+			t.Errorf("%s = %v, want [%s]", repo, branches[repo], branch)
+		}
+	}
+}
+
+func TestFetchRepoHistoriesMergesBranches(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	client := &fakeClient{histories: map[string][]models.Commit{
+		"main":    {{SHA: "c3", Date: day(3)}, {SHA: "c1", Date: day(1)}},
+		"release": {{SHA: "c4", Date: day(4)}, {SHA: "c1", Date: day(1)}},
+	}}
+	api := models.Repository{NameWithOwner: "acme/api", DefaultBranchName: "main"}
+	web := models.Repository{NameWithOwner: "acme/web", DefaultBranchName: "main"}
+	m := New(context.Background(), client)
+	m.filters = models.NewFilterOptions()
+	m.branches = map[string][]string{"acme/api": {"main", "release"}}
+
+	groups := [][]forge.HistoryRef{
+		{{Repository: api, Branch: "main"}, {Repository: api, Branch: "release"}},
+		{{Repository: web, Branch: "main"}},
+	}
+	results := m.fetchRepoHistories(context.Background(), groups)
+
+	if len(results) != 2 || len(client.refs) != 3 {
+		t.Fatalf("results = %d, refs = %d, want 2 results from 3 refs", len(results), len(client.refs))
+	}
+	merged := results[0]
+	if len(merged.Commits) != 3 || len(merged.Commits[2].Branches) != 2 || merged.Cursors["release"] != "page2" {
+		t.Errorf("merged = %+v", merged)
+	}
+	if results[1].MultiBranch() || results[1].Commits[0].Branches != nil {
+		t.Errorf("single branch repo should not be annotated: %+v", results[1])
+	}
+
+	m.repoCommits = results
+	msg := m.loadMoreCommits(commitview.LoadMoreMsg{RepoName: "acme/api"})().(moreCommitsLoadedMsg)
+	if last := client.refs[len(client.refs)-1]; last.Branch != "release" || last.Cursor != "page2" {
+		t.Errorf("load more fetched %+v, want release from page2", last)
+	}
+
+	updated := updateRepoCommits(m.repoCommits, msg)
+	if len(updated[0].Commits) != 3 || updated[0].HasMore {
+		t.Errorf("after load more = %+v", updated[0])
+	}
+}
+
+func TestFetchAllBranchesFlagsUnmerged(t *testing.T) {
+	client := &fakeClient{histories: map[string][]models.Commit{
+		"main":    {{SHA: "base"}},
+		"feature": {{SHA: "wip"}, {SHA: "base"}},
+	}}
+	repo := models.Repository{NameWithOwner: "acme/api", DefaultBranchName: "main"}
+	m := New(context.Background(), client)
+	m.filters = models.FilterOptions{PerPage: 10, AllBranches: true}
+
+	rc := m.fetchAllBranches(context.Background(), repo)
+
+	if !rc.AllBranches || len(rc.Branches) != 2 || rc.Branches[0] != "main" {
+		t.Fatalf("repo commits = %+v", rc)
+	}
+	for _, c := range rc.Commits {
+		if want := c.SHA == "wip"; rc.IsUnmerged(c) != want {
+			t.Errorf("%s unmerged = %v, want %v", c.SHA, rc.IsUnmerged(c), want)
+		}
+	}
+}
+
+func TestCompareFromCommitView(t *testing.T) {
+	ledger := models.Repository{NameWithOwner: "acme/ledger", DefaultBranchName: "main"}
+	client := &fakeClient{histories: map[string][]models.Commit{"release": {{SHA: "r1"}, {SHA: "r2"}}}}
+	m := New(context.Background(), client)
+	m.repoCommits = []models.RepoCommits{{Repository: ledger, Branch: "main", Commits: []models.Commit{{SHA: "m1"}}}}
+	m.width, m.height = 120, 40
+	m.commitView = commitview.New(m.repoCommits, m.width, m.height)
+	m.state = stateCommitView
+
+	updated, cmd := m.Update(commitview.CompareMsg{Repo: ledger, Base: "main", Head: "missing"})
+	updated, _ = updated.(Model).Update(cmd())
+	if m = updated.(Model); m.state != stateCommitView || !strings.Contains(m.commitView.View(), "could not compare main...missing") {
+		t.Fatalf("state = %v, want commit view with an error status", m.state)
+	}
+
+	updated, cmd = m.Update(commitview.CompareMsg{Repo: ledger, Base: "main", Head: "release"})
+	updated, _ = updated.(Model).Update(cmd())
+	if m = updated.(Model); m.state != stateCompareView || !strings.Contains(m.compareView.View(), "2 ahead, 0 behind") {
+		t.Fatalf("state = %v, want compare view", m.state)
+	}
+
+	updated, _ = m.Update(commitview.BackMsg{})
+	if m = updated.(Model); m.state != stateCommitView {
+		t.Errorf("state = %v, want commit view after back", m.state)
+	}
+}
+
+func TestRestartDropsStaleComparisonAndDetail(t *testing.T) {
+	ledger := models.Repository{NameWithOwner: "acme/ledger", DefaultBranchName: "main"}
+	client := &fakeClient{histories: map[string][]models.Commit{"release": {{SHA: "r1"}}}}
+	m := New(context.Background(), client)
+	m.repos = []models.Repository{ledger}
+	m.repoCommits = []models.RepoCommits{{Repository: ledger, Branch: "main", Commits: []models.Commit{{SHA: "m1"}}}}
+	m.width, m.height = 120, 40
+	m.commitView = commitview.New(m.repoCommits, m.width, m.height)
+	m.state = stateCommitView
+
+	_, compareCmd := m.Update(commitview.CompareMsg{Repo: ledger, Base: "main", Head: "release"})
+	_, detailCmd := m.Update(commitview.LoadDetailMsg{Repo: ledger, SHA: "m1"})
+	m, _ = m.restart()
+	m.repoCommits = []models.RepoCommits{{Repository: ledger, Branch: "main", Commits: []models.Commit{{SHA: "m1"}}}}
+	m.state = stateCommitView
+
+	updated, _ := m.Update(compareCmd())
+	updated, _ = updated.(Model).Update(detailCmd())
+	if m = updated.(Model); m.state != stateCommitView {
+		t.Errorf("state = %v, want the stale comparison dropped", m.state)
+	}
+	if m.repoCommits[0].Commits[0].HasDetail() {
+		t.Error("stale commit detail should be dropped")
+	}
+}
+
+func TestCommitDetailFromCommitView(t *testing.T) {
+	ledger := models.Repository{NameWithOwner: "acme/ledger", DefaultBranchName: "main"}
+	m := New(context.Background(), &fakeClient{})
+	m.repoCommits = []models.RepoCommits{{Repository: ledger, Branch: "main", Commits: []models.Commit{{SHA: "m1"}, {SHA: "missing"}}}}
+	m.width, m.height = 120, 40
+	m.commitView = commitview.New(m.repoCommits, m.width, m.height)
+	m.state = stateCommitView
+
+	updated, cmd := m.Update(commitview.LoadDetailMsg{Repo: ledger, SHA: "m1"})
+	updated, _ = updated.(Model).Update(cmd())
+	m = updated.(Model)
+	if c := m.repoCommits[0].Commits[0]; !c.HasDetail() || len(c.Files) != 1 || c.Parents[0] != "p1" {
+		t.Errorf("commit = %+v, want detail stored in the loaded commits", c)
+	}
+
+	updated, cmd = m.Update(commitview.LoadDetailMsg{Repo: ledger, SHA: "missing"})
+	updated, _ = updated.(Model).Update(cmd())
+	m = updated.(Model)
+	if c := m.repoCommits[0].Commits[1]; c.HasDetail() {
+		t.Errorf("commit = %+v, want no detail after an error", c)
+	}
+	if m.state != stateCommitView {
+		t.Errorf("state = %v, want commit view after a detail error", m.state)
+	}
+}
+
+func TestMergeRepoCommits(t *testing.T) {
+	current := []models.RepoCommits{
+		{Repository: models.Repository{NameWithOwner: "owner/one"}, Status: models.RepoStatusLoading},
+		{Repository: models.Repository{NameWithOwner: "owner/two"}, Status: models.RepoStatusLoading},
+	}
+	loaded := []models.RepoCommits{
+		{Repository: models.Repository{NameWithOwner: "owner/two"}, Commits: make([]models.Commit, 3)},
+	}
+
+	merged := mergeRepoCommits(current, loaded)
+
+	if merged[0].Status != models.RepoStatusLoading {
+		t.Error("owner/one should still be loading")
+	}
+	if merged[1].Status != models.RepoStatusLoaded || len(merged[1].Commits) != 3 {
+		t.Errorf("owner/two = %+v", merged[1])
+	}
+	if current[1].Status != models.RepoStatusLoading {
+		t.Error("mergeRepoCommits should not modify its input")
+	}
+}
+
+func TestBranchLoadFailureKeepsOtherRepos(t *testing.T) {
+	client := &fakeClient{
+		histories:  map[string][]models.Commit{"main": nil, "release": nil},
+		branchErrs: map[string]error{"acme/web": forge.ErrForbidden},
+	}
+	api := models.Repository{NameWithOwner: "acme/api", DefaultBranchName: "main"}
+	web := models.Repository{NameWithOwner: "acme/web", DefaultBranchName: "main"}
+	m := New(context.Background(), client)
+	m.state = stateRepoSelect
+
+	updated, cmd := m.Update(reposelect.DoneMsg{Selected: []models.Repository{api, web}})
+	for cmd != nil && updated.(Model).state == stateLoadingBranches {
+		updated, cmd = updated.(Model).Update(cmd())
+	}
+	m = updated.(Model)
+	if m.state != stateFilterForm {
+		t.Fatalf("state = %v, want the filter form", m.state)
+	}
+	if len(m.repoBranches[0].Branches) != 2 || !errors.Is(m.repoBranches[1].Err, forge.ErrForbidden) {
+		t.Errorf("repoBranches = %+v", m.repoBranches)
+	}
+	if view := m.filterForm.View(); !strings.Contains(view, "could not list branches") {
+		t.Error("filter form should show the branch error on the failed repo")
+	}
+	if branches := m.filterForm.Branches(); len(branches["acme/web"]) != 1 || branches["acme/web"][0] != "main" {
+		t.Errorf("acme/web branches = %v, want the default branch", branches["acme/web"])
+	}
+
+	updated, _ = m.Update(filterform.PickBranchMsg{Index: 0})
+	updated, _ = updated.(Model).Update(branchselect.DoneMsg{Repo: api, Branches: []string{"release"}})
+	if m = updated.(Model); m.state != stateFilterForm {
+		t.Errorf("state = %v, want the branch walk to skip the failed repo", m.state)
+	}
+}
+
+func TestStaleResultsAreDropped(t *testing.T) {
+	m := New(context.Background(), nil)
+	m.loadID = 2
+	m.state = stateLoadingCommits
+
+	updated, cmd := m.Update(commitsLoadedMsg{loadID: 1, repoCommits: []models.RepoCommits{{}}})
+	if cmd != nil {
+		t.Error("expected no command for a stale result")
+	}
+	if updated.(Model).state != stateLoadingCommits {
+		t.Error("stale result should not change state")
+	}
+}
+
+func TestRestartCancelsLoad(t *testing.T) {
+	m := New(context.Background(), nil)
+	m = m.startLoad(1)
+	ctx := m.loadCtx
+
+	m, _ = m.restart()
+
+	if ctx.Err() == nil {
+		t.Error("restart should cancel the in-flight load")
+	}
+}
+
+func TestFetchHistoriesMarksBatchFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	m := New(context.Background(), github.NewRESTClient(server.URL, "test-token"))
+	m.filters = models.NewFilterOptions()
+	refs := []forge.HistoryRef{
+		{Repository: models.Repository{NameWithOwner: "owner/one"}},
+		{Repository: models.Repository{NameWithOwner: "owner/two"}},
+	}
+
+	results := m.fetchHistories(context.Background(), refs)
+	for _, rc := range results {
+		if !rc.Failed() || rc.Err == nil {
+			t.Errorf("%s = %+v, want failed", rc.Repository.NameWithOwner, rc)
+		}
+	}
+}
+
+func TestUpdateRepoCommitsWithError(t *testing.T) {
+	current := []models.RepoCommits{
+		{Repository: models.Repository{NameWithOwner: "owner/one"}, Commits: make([]models.Commit, 2), HasMore: true},
+	}
+
+	updated := updateRepoCommits(current, moreCommitsLoadedMsg{repoName: "owner/one", err: errors.New("boom")})
+
+	if !updated[0].Failed() || len(updated[0].Commits) != 2 {
+		t.Errorf("updated = %+v, want failed with commits kept", updated[0])
+	}
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/github"
+	"github.com/tkozakas/gh-log/internal/store"
+)
+
+type errorKind int
+
+const (
+	errorUnknown errorKind = iota
+	errorAuth
+	errorNotFound
+	errorRateLimited
+	errorNetwork
+)
+
+func (k errorKind) String() string {
+	switch k {
+	case errorAuth:
+		return "authentication"
+	case errorNotFound:
+		return "not found"
+	case errorRateLimited:
+		return "rate limited"
+	case errorNetwork:
+		return "network"
+	default:
+		return "unexpected"
+	}
+}
+
+func errorHint(err error) string {
+	var rateErr *github.RateLimitError
+	if errors.As(err, &rateErr) {
+		if reset := rateErr.Reset; !reset.IsZero() {
+			return fmt.Sprintf("GitHub's API rate limit was hit. It resets at %s.", reset.Local().Format("15:04"))
+		}
+		return "GitHub's API rate limit was hit. Wait a moment before retrying."
+	}
+
+	if errors.Is(err, store.ErrNotCached) {
+		return "Nothing cached yet. Run ghlog once without --offline to populate the cache."
+	}
+
+	var ssoErr *github.SSOError
+	if errors.As(err, &ssoErr) && ssoErr.URL != "" {
+		return "Authorize your token for this organization: " + ssoErr.URL
+	}
+
+	switch classifyError(err) {
+	case errorAuth:
+		return tokenHints[errorForge(err)]
+	case errorNotFound:
+		return "The repository or branch may have been renamed, deleted or made private."
+	case errorRateLimited:
+		return errorForge(err) + "'s API rate limit was hit. Wait a moment before retrying."
+	case errorNetwork:
+		return "Check your connection or VPN, then retry."
+	default:
+		return ""
+	}
+}
+
+var tokenHints = map[string]string{
+	forge.GitHub: "Check GH_TOKEN or run 'gh auth login', then retry.",
+	forge.GitLab: "Check GITLAB_TOKEN, then retry.",
+	forge.Gitea:  "Check GITEA_TOKEN, then retry.",
+}
+
+func errorForge(err error) string {
+	var httpErr *forge.HTTPError
+	if errors.As(err, &httpErr) && httpErr.Forge != "" {
+		return httpErr.Forge
+	}
+	return forge.GitHub
+}
+
+func classifyError(err error) errorKind {
+	switch {
+	case err == nil:
+		return errorUnknown
+	case errors.Is(err, forge.ErrRateLimited):
+		return errorRateLimited
+	case errors.Is(err, forge.ErrUnauthorized),
+		errors.Is(err, forge.ErrForbidden),
+		errors.Is(err, github.ErrSSO),
+		errors.Is(err, github.ErrGHNotAuthenticated):
+		return errorAuth
+	case errors.Is(err, forge.ErrNotFound):
+		return errorNotFound
+	}
+
+	var httpErr *forge.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode >= http.StatusInternalServerError {
+		return errorNetwork
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return errorNetwork
+	}
+	return errorUnknown
+}
@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/github"
+	"github.com/tkozakas/gh-log/internal/store"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected errorKind
+	}{
+		{"nil", nil, errorUnknown},
+		{"plain", errors.New("boom"), errorUnknown},
+		{"unauthorized", &github.APIError{StatusCode: 401, Message: "Bad credentials"}, errorAuth},
+		{"forbidden", &github.APIError{StatusCode: 403, Message: "Resource not accessible"}, errorAuth},
+		{"primaryRateLimit", &github.RateLimitError{APIError: github.APIError{StatusCode: 403}}, errorRateLimited},
+		{"secondaryRateLimit", &github.RateLimitError{APIError: github.APIError{StatusCode: 429}, Secondary: true}, errorRateLimited},
+		{"sso", &github.SSOError{APIError: github.APIError{StatusCode: 403}, URL: "https://github.com/orgs/acme/sso"}, errorAuth},
+		{"emptyRepository", forge.ErrEmptyRepository, errorUnknown},
+		{"notFound", &github.APIError{StatusCode: 404, Message: "Not Found"}, errorNotFound},
+		{"serverError", &github.APIError{StatusCode: 502}, errorNetwork},
+		{"wrapped", fmt.Errorf("loading: %w", &github.APIError{StatusCode: 404}), errorNotFound},
+		{"ghNotAuthenticated", github.ErrGHNotAuthenticated, errorAuth},
+		{"forgeUnauthorized", &forge.HTTPError{StatusCode: 401}, errorAuth},
+		{"forgeServerError", &forge.HTTPError{StatusCode: 503}, errorNetwork},
+		{"dns", &net.DNSError{Err: "no such host", Name: "api.github.com"}, errorNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifyError(tt.err); got != tt.expected {
+				t.Errorf("classifyError() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestErrorHint(t *testing.T) {
+	reset := time.Date(2024, 6, 15, 14, 30, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		err      error
+		contains string
+	}{
+		{"rateLimitReset", &github.RateLimitError{Reset: reset}, "resets at 14:30"},
+		{"sso", &github.SSOError{URL: "https://github.com/orgs/acme/sso"}, "https://github.com/orgs/acme/sso"},
+		{"githubAuth", &github.APIError{Forge: forge.GitHub, StatusCode: 401}, "GH_TOKEN"},
+		{"gitlabAuth", &forge.HTTPError{Forge: forge.GitLab, StatusCode: 401}, "GITLAB_TOKEN"},
+		{"giteaAuth", &forge.HTTPError{Forge: forge.Gitea, StatusCode: 403}, "GITEA_TOKEN"},
+		{"gitlabRateLimit", &forge.HTTPError{Forge: forge.GitLab, StatusCode: 429}, "GitLab's API rate limit"},
+		{"notFound", forge.ErrNotFound, "renamed, deleted"},
+		{"notCached", store.ErrNotCached, "without --offline"},
+		{"unknown", errors.New("boom"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorHint(tt.err)
+			if tt.contains == "" && got != "" {
+				t.Errorf("errorHint() = %q, want empty", got)
+			}
+			if !strings.Contains(got, tt.contains) {
+				t.Errorf("errorHint() = %q, want it to contain %q", got, tt.contains)
+			}
+		})
+	}
+}
+
+func TestErrorStateRetry(t *testing.T) {
+	m := New(context.Background(), nil)
+	retried := false
+	retry := func() tea.Msg {
+		retried = true
+		return nil
+	}
+
+	updated, _ := m.Update(errMsg{err: errors.New("boom"), retry: retry})
+	m = updated.(Model)
+	if m.state != stateError {
+		t.Fatalf("state = %v, want stateError", m.state)
+	}
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	m = updated.(Model)
+	if m.state != stateLoading {
+		t.Errorf("state = %v, want stateLoading", m.state)
+	}
+	if cmd == nil {
+		t.Fatal("expected retry command")
+	}
+	cmd()
+	if !retried {
+		t.Error("retry command was not the failed operation")
+	}
+}
+
+func TestErrorStateBack(t *testing.T) {
+	m := New(context.Background(), nil)
+	m.state = stateCommitView
+
+	updated, _ := m.Update(errMsg{err: errors.New("boom")})
+	updated, _ = updated.(Model).Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	if got := updated.(Model).state; got != stateCommitView {
+		t.Errorf("state = %v, want stateCommitView", got)
+	}
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const maxWorkers = 4
+
+type streamDoneMsg struct{ loadID int }
+
+func runPool[J any](ctx context.Context, jobs []J, work func(context.Context, J) tea.Msg) <-chan tea.Msg {
+	out := make(chan tea.Msg)
+	queue := make(chan J)
+
+	go func() {
+		defer close(queue)
+		for _, job := range jobs {
+			select {
+			case queue <- job:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range min(maxWorkers, len(jobs)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				if ctx.Err() != nil {
+					return
+				}
+				msg := work(ctx, job)
+				select {
+				case out <- msg:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
+
+func waitForStream(loadID int, stream <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		msg, ok := <-stream
+		if !ok {
+			return streamDoneMsg{loadID: loadID}
+		}
+		return msg
+	}
+}
+
+func chunk[T any](items []T, parts int) [][]T {
+	if len(items) == 0 || parts <= 0 {
+		return nil
+	}
+
+	size := (len(items) + parts - 1) / parts
+	chunks := make([][]T, 0, parts)
+	for start := 0; start < len(items); start += size {
+		chunks = append(chunks, items[start:min(start+size, len(items))])
+	}
+	return chunks
+}
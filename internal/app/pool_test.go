@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestRunPoolProcessesAllJobs(t *testing.T) {
+	jobs := []int{1, 2, 3, 4, 5, 6, 7}
+
+	stream := runPool(context.Background(), jobs, func(ctx context.Context, n int) tea.Msg {
+		return n * 2
+	})
+
+	sum := 0
+	for msg := range stream {
+		sum += msg.(int)
+	}
+	if sum != 56 {
+		t.Errorf("sum = %d, want 56", sum)
+	}
+}
+
+func TestRunPoolBoundsConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	jobs := make([]int, 20)
+
+	stream := runPool(context.Background(), jobs, func(ctx context.Context, _ int) tea.Msg {
+		n := running.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+		return nil
+	})
+	for range stream {
+	}
+
+	if got := peak.Load(); got > maxWorkers {
+		t.Errorf("peak concurrency = %d, want <= %d", got, maxWorkers)
+	}
+}
+
+func TestRunPoolCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var started atomic.Int32
+	jobs := make([]int, 50)
+
+	stream := runPool(ctx, jobs, func(ctx context.Context, _ int) tea.Msg {
+		started.Add(1)
+		<-ctx.Done()
+		return nil
+	})
+	cancel()
+
+	done := make(chan struct{})
+	go func() {
+		for range stream {
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream was not closed after cancellation")
+	}
+	if got := started.Load(); got > maxWorkers {
+		t.Errorf("started = %d jobs after cancellation, want <= %d", got, maxWorkers)
+	}
+}
+
+func TestWaitForStream(t *testing.T) {
+	stream := make(chan tea.Msg, 1)
+	stream <- "result"
+	close(stream)
+
+	if got := waitForStream(3, stream)(); got != "result" {
+		t.Errorf("first msg = %v, want %q", got, "result")
+	}
+	if got := waitForStream(3, stream)(); got != (streamDoneMsg{loadID: 3}) {
+		t.Errorf("second msg = %v, want streamDoneMsg{3}", got)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    int
+		parts    int
+		expected []int
+	}{
+		{"empty", 0, 4, nil},
+		{"fewerThanParts", 2, 4, []int{1, 1}},
+		{"even", 8, 4, []int{2, 2, 2, 2}},
+		{"uneven", 15, 4, []int{4, 4, 4, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := chunk(make([]int, tt.items), tt.parts)
+			if len(chunks) != len(tt.expected) {
+				t.Fatalf("got %d chunks, want %d", len(chunks), len(tt.expected))
+			}
+			for i, c := range chunks {
+				if len(c) != tt.expected[i] {
+					t.Errorf("chunk %d has %d items, want %d", i, len(c), tt.expected[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package cache
+
+import "testing"
+
+type entry struct {
+	Name  string
+	Count int
+}
+
+func TestStorePutGet(t *testing.T) {
+	s := New(t.TempDir())
+
+	if err := s.Put("repos/owner/repo", entry{Name: "repo", Count: 3}); err != nil {
+		t.Fatalf("Put() error: %v", err)
+	}
+
+	var got entry
+	ok, err := s.Get("repos/owner/repo", &got)
+	if err != nil || !ok {
+		t.Fatalf("Get() = %v, %v, want hit", ok, err)
+	}
+	if got != (entry{Name: "repo", Count: 3}) {
+		t.Errorf("Get() = %+v", got)
+	}
+}
+
+func TestStoreMiss(t *testing.T) {
+	s := New(t.TempDir())
+
+	var got entry
+	ok, err := s.Get("missing", &got)
+	if err != nil || ok {
+		t.Errorf("Get() = %v, %v, want miss without error", ok, err)
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"hosts": ["github.com", "ghes.example.com"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !slices.Equal(c.Hosts, []string{"github.com", "ghes.example.com"}) {
+		t.Errorf("Hosts = %v", c.Hosts)
+	}
+}
+
+func TestLoadMissing(t *testing.T) {
+	c, err := Load(filepath.Join(t.TempDir(), "config.json"))
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(c.Hosts) != 0 {
+		t.Errorf("Hosts = %v, want none", c.Hosts)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"hosts": `), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Load(path); err == nil {
+		t.Error("expected error for malformed config")
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ghlog", "config.json")
+	want := Config{Hosts: []string{"ghes.example.com"}, Repos: []string{"golang/go"}}
+
+	if err := Save(path, want); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !slices.Equal(got.Hosts, want.Hosts) || !slices.Equal(got.Repos, want.Repos) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
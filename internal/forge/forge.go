@@ -0,0 +1,167 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+var (
+	ErrUnauthorized    = errors.New("unauthorized")
+	ErrForbidden       = errors.New("forbidden")
+	ErrNotFound        = errors.New("not found")
+	ErrRateLimited     = errors.New("rate limited")
+	ErrEmptyRepository = errors.New("repository is empty")
+)
+
+const (
+	GitHub = "GitHub"
+	GitLab = "GitLab"
+	Gitea  = "Gitea"
+)
+
+type PartialError struct {
+	Errs []error
+}
+
+func (e *PartialError) Error() string {
+	messages := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *PartialError) Unwrap() []error {
+	return e.Errs
+}
+
+type Provider interface {
+	ListRepositories(ctx context.Context) ([]models.Repository, error)
+	GetRepository(ctx context.Context, nameWithOwner string) (models.Repository, error)
+	SearchRepositories(ctx context.Context, query string) ([]models.Repository, error)
+	ListBranches(ctx context.Context, repo models.Repository) ([]models.Branch, error)
+	GetCommits(ctx context.Context, repo models.Repository, branch string, filters models.FilterOptions, page int) (models.CommitPage, error)
+	GetCommit(ctx context.Context, repo models.Repository, sha string) (models.Commit, error)
+	GetHistories(ctx context.Context, refs []HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error)
+	Compare(ctx context.Context, repo models.Repository, base, head string) (models.Comparison, error)
+	RateLimit() models.RateLimit
+}
+
+const MaxAllBranches = 100
+
+type HistoryRef struct {
+	Repository models.Repository
+	Branch     string
+	Cursor     string
+}
+
+func FailedHistory(ref HistoryRef, err error) models.RepoCommits {
+	return models.RepoCommits{
+		Repository: ref.Repository,
+		Branch:     ref.Branch,
+		Status:     models.RepoStatusFailed,
+		Err:        err,
+	}
+}
+
+func BranchRefs(ctx context.Context, p Provider, repo models.Repository, filters models.FilterOptions) ([]HistoryRef, error) {
+	branches, err := p.ListBranches(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	models.SortBranchesByRecency(branches)
+
+	since, _ := time.Parse(time.DateOnly, filters.DateFrom)
+	var refs []HistoryRef
+	for _, b := range branches {
+		if !since.IsZero() && !b.CommittedAt.IsZero() && b.CommittedAt.Before(since) {
+			continue
+		}
+		ref := HistoryRef{Repository: repo, Branch: b.Name}
+		if b.Name == repo.DefaultBranchName {
+			refs = append([]HistoryRef{ref}, refs...)
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	return refs[:min(len(refs), MaxAllBranches)], nil
+}
+
+func UnmergedCommits(ctx context.Context, p Provider, repo models.Repository, histories []models.RepoCommits) (map[string]bool, error) {
+	base := repo.DefaultBranchName
+	onBase := make(map[string]bool)
+	for _, h := range histories {
+		if h.Branch == base {
+			for _, c := range h.Commits {
+				onBase[c.SHA] = true
+			}
+		}
+	}
+
+	unmerged := make(map[string]bool)
+	var errs []error
+	for _, h := range histories {
+		if base == "" || h.Branch == base || h.Failed() {
+			continue
+		}
+		if !slices.ContainsFunc(h.Commits, func(c models.Commit) bool { return !onBase[c.SHA] }) {
+			continue
+		}
+		comparison, err := p.Compare(ctx, repo, base, h.Branch)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("compare %s...%s: %w", base, h.Branch, err))
+			continue
+		}
+		for _, c := range comparison.Commits {
+			unmerged[c.SHA] = true
+		}
+	}
+	return unmerged, errors.Join(errs...)
+}
+
+func MergeHistories(histories []models.RepoCommits) models.RepoCommits {
+	if len(histories) == 0 {
+		return models.RepoCommits{}
+	}
+
+	merged := models.RepoCommits{
+		Repository: histories[0].Repository,
+		Branch:     histories[0].Branch,
+		Cursors:    make(map[string]string),
+	}
+	var errs []error
+	for _, h := range histories {
+		merged.Branches = append(merged.Branches, h.Branch)
+		if h.Failed() {
+			errs = append(errs, fmt.Errorf("%s: %w", h.Branch, h.Err))
+			continue
+		}
+
+		commits := make([]models.Commit, len(h.Commits))
+		for i, c := range h.Commits {
+			c.Branches = []string{h.Branch}
+			commits[i] = c
+		}
+		merged.Commits = models.MergeCommits(merged.Commits, commits)
+		if h.HasMore {
+			merged.Cursors[h.Branch] = h.Cursor
+			merged.HasMore = true
+		}
+		if h.SyncedAt.After(merged.SyncedAt) {
+			merged.SyncedAt = h.SyncedAt
+		}
+		merged.Partial = merged.Partial || h.Partial
+	}
+
+	if len(errs) > 0 {
+		merged.Status = models.RepoStatusFailed
+		merged.Err = errors.Join(errs...)
+	}
+	return merged
+}
@@ -0,0 +1,118 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+func TestMergeHistories(t *testing.T) {
+	repo := models.Repository{NameWithOwner: "acme/api"}
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	histories := []models.RepoCommits{
+		{
+			Repository: repo,
+			Branch:     "main",
+			Commits:    []models.Commit{{SHA: "c3", Date: day(3)}, {SHA: "c1", Date: day(1)}},
+		},
+		{
+			Repository: repo,
+			Branch:     "release",
+			Commits:    []models.Commit{{SHA: "c4", Date: day(4)}, {SHA: "c1", Date: day(1)}},
+			HasMore:    true,
+			Cursor:     "next",
+		},
+		{Repository: repo, Branch: "hotfix", Status: models.RepoStatusFailed, Err: ErrNotFound},
+	}
+
+	merged := MergeHistories(histories)
+
+	if !slices.Equal(merged.Branches, []string{"main", "release", "hotfix"}) {
+		t.Errorf("Branches = %v", merged.Branches)
+	}
+	var shas []string
+	for _, c := range merged.Commits {
+		shas = append(shas, c.SHA)
+	}
+	if !slices.Equal(shas, []string{"c4", "c3", "c1"}) {
+		t.Errorf("commits = %v, want c4 c3 c1", shas)
+	}
+	if got := merged.Commits[2].Branches; !slices.Equal(got, []string{"main", "release"}) {
+		t.Errorf("c1 branches = %v, want main and release", got)
+	}
+	if !merged.HasMore || merged.Cursors["release"] != "next" || len(merged.Cursors) != 1 {
+		t.Errorf("hasMore = %v, cursors = %v", merged.HasMore, merged.Cursors)
+	}
+	if !merged.Failed() || !errors.Is(merged.Err, ErrNotFound) {
+		t.Errorf("status = %v, err = %v, want failed with not found", merged.Status, merged.Err)
+	}
+	if histories[0].Commits[0].Branches != nil {
+		t.Error("MergeHistories should not modify its input")
+	}
+}
+
+func TestBranchRefs(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	p := &fakeProvider{branches: []models.Branch{
+		{Name: "stale", CommittedAt: day(1)},
+		{Name: "main", CommittedAt: day(5)},
+		{Name: "feature", CommittedAt: day(9)},
+		{Name: "unknown"},
+	}}
+	repo := models.Repository{NameWithOwner: "acme/api", DefaultBranchName: "main"}
+
+	tests := []struct {
+		name     string
+		filters  models.FilterOptions
+		expected []string
+	}{
+		{"allBranches", models.FilterOptions{}, []string{"main", "feature", "stale", "unknown"}},
+		{"dateWindow", models.FilterOptions{DateFrom: "2024-03-03"}, []string{"main", "feature", "unknown"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			refs, err := BranchRefs(context.Background(), p, repo, tt.filters)
+			if err != nil {
+				t.Fatalf("BranchRefs() error: %v", err)
+			}
+			var got []string
+			for _, ref := range refs {
+				got = append(got, ref.Branch)
+			}
+			if !slices.Equal(got, tt.expected) {
+				t.Errorf("BranchRefs() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUnmergedCommits(t *testing.T) {
+	p := &fakeProvider{ahead: map[string][]models.Commit{"feature": {{SHA: "wip"}}}}
+	repo := models.Repository{NameWithOwner: "acme/api", DefaultBranchName: "main"}
+	histories := []models.RepoCommits{
+		{Branch: "main", Commits: []models.Commit{{SHA: "m2"}, {SHA: "m1"}}},
+		{Branch: "feature", Commits: []models.Commit{{SHA: "wip"}, {SHA: "old"}}},
+		{Branch: "hotfix", Commits: []models.Commit{{SHA: "m1"}}},
+	}
+
+	unmerged, err := UnmergedCommits(context.Background(), p, repo, histories)
+	if err != nil {
+		t.Fatalf("UnmergedCommits() error: %v", err)
+	}
+	if !unmerged["wip"] || unmerged["old"] || len(unmerged) != 1 {
+		t.Errorf("unmerged = %v, want only wip", unmerged)
+	}
+	if !slices.Equal(p.compared, []string{"feature"}) {
+		t.Errorf("compared %v, want only branches with commits missing from main", p.compared)
+	}
+
+	p.err = ErrForbidden
+	if _, err := UnmergedCommits(context.Background(), p, repo, histories); !errors.Is(err, ErrForbidden) {
+		t.Errorf("error = %v, want ErrForbidden", err)
+	}
+}
@@ -0,0 +1,63 @@
+package forge
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type PageLinks struct {
+	Next int
+	Last int
+}
+
+func ParsePageLinks(header string) PageLinks {
+	var links PageLinks
+	for _, rel := range parseLinkHeader(header) {
+		switch rel.name {
+		case "next":
+			links.Next = pageFromURL(rel.url)
+		case "last":
+			links.Last = pageFromURL(rel.url)
+		}
+	}
+	return links
+}
+
+type linkRel struct {
+	name string
+	url  string
+}
+
+func parseLinkHeader(header string) []linkRel {
+	var rels []linkRel
+	for _, part := range strings.Split(header, ",") {
+		segments := strings.Split(strings.TrimSpace(part), ";")
+		if len(segments) < 2 {
+			continue
+		}
+
+		target := strings.TrimSpace(segments[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		target = target[1 : len(target)-1]
+
+		for _, param := range segments[1:] {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && name == "rel" {
+				rels = append(rels, linkRel{name: strings.Trim(value, `"`), url: target})
+			}
+		}
+	}
+	return rels
+}
+
+func pageFromURL(rawURL string) int {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0
+	}
+	page, _ := strconv.Atoi(u.Query().Get("page"))
+	return page
+}
@@ -0,0 +1,32 @@
+package forge
+
+import "testing"
+
+func TestParsePageLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected PageLinks
+	}{
+		{"empty", "", PageLinks{}},
+		{
+			name:     "nextAndLast",
+			header:   `<https://api.github.com/repositories/1/commits?per_page=50&page=2>; rel="next", <https://api.github.com/repositories/1/commits?per_page=50&page=25>; rel="last"`,
+			expected: PageLinks{Next: 2, Last: 25},
+		},
+		{
+			name:     "lastPage",
+			header:   `<https://api.github.com/repositories/1/commits?per_page=50&page=1>; rel="first", <https://api.github.com/repositories/1/commits?per_page=50&page=24>; rel="prev"`,
+			expected: PageLinks{},
+		},
+		{"malformed", `https://api.github.com/x?page=2; rel="next"`, PageLinks{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParsePageLinks(tt.header); got != tt.expected {
+				t.Errorf("ParsePageLinks() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
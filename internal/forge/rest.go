@@ -0,0 +1,103 @@
+package forge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second
+
+const emptyRepositoryMessage = "Git Repository is empty."
+
+type RESTClient struct {
+	forge   string
+	baseURL string
+	header  http.Header
+	client  *http.Client
+}
+
+type HTTPError struct {
+	Forge      string
+	StatusCode int
+	Message    string
+}
+
+func NewRESTClient(forge, baseURL string, header http.Header) *RESTClient {
+	return &RESTClient{
+		forge:   forge,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		header:  header,
+		client:  &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (c *RESTClient) Get(ctx context.Context, endpoint string, result any) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/"+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return nil, &HTTPError{Forge: c.forge, StatusCode: resp.StatusCode, Message: ErrorMessage(body)}
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", endpoint, err)
+	}
+	return resp.Header, nil
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+func (e *HTTPError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrEmptyRepository:
+		return e.StatusCode == http.StatusConflict && e.Message == emptyRepositoryMessage
+	default:
+		return false
+	}
+}
+
+func ErrorMessage(body []byte) string {
+	var response struct {
+		Message any    `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(body, &response) == nil {
+		switch {
+		case response.Error != "":
+			return response.Error
+		case response.Message != nil:
+			return fmt.Sprint(response.Message)
+		}
+	}
+	return strings.TrimSpace(string(body))
+}
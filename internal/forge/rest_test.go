@@ -0,0 +1,48 @@
+package forge
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{"github", `{"message": "Bad credentials", "documentation_url": "https://docs.github.com"}`, "Bad credentials"},
+		{"gitlab", `{"message": {"base": ["invalid"]}}`, "map[base:[invalid]]"},
+		{"oauth", `{"error": "invalid_token"}`, "invalid_token"},
+		{"plain", " upstream timeout \n", "upstream timeout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorMessage([]byte(tt.body)); got != tt.expected {
+				t.Errorf("ErrorMessage() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHTTPErrorEmptyRepository(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      *HTTPError
+		expected bool
+	}{
+		{"empty", &HTTPError{StatusCode: http.StatusConflict, Message: "Git Repository is empty."}, true},
+		{"conflict", &HTTPError{StatusCode: http.StatusConflict, Message: "Reference update failed"}, false},
+		{"notFound", &HTTPError{StatusCode: http.StatusNotFound, Message: "Git Repository is empty."}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, ErrEmptyRepository); got != tt.expected {
+				t.Errorf("errors.Is(%v, ErrEmptyRepository) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
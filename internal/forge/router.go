@@ -0,0 +1,183 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+type Router struct {
+	hosts     []string
+	providers map[string]Provider
+}
+
+func NewRouter() *Router {
+	return &Router{providers: make(map[string]Provider)}
+}
+
+func (r *Router) Register(host string, p Provider) {
+	if _, ok := r.providers[host]; !ok {
+		r.hosts = append(r.hosts, host)
+	}
+	r.providers[host] = p
+}
+
+func (r *Router) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	return r.collect(func(p Provider) ([]models.Repository, error) {
+		return p.ListRepositories(ctx)
+	})
+}
+
+func (r *Router) GetRepository(ctx context.Context, nameWithOwner string) (models.Repository, error) {
+	host, name := r.splitHost(nameWithOwner)
+	p, err := r.provider(host)
+	if err != nil {
+		return models.Repository{}, err
+	}
+	repo, err := p.GetRepository(ctx, name)
+	if err != nil {
+		return models.Repository{}, err
+	}
+	repo.Host = host
+	return repo, nil
+}
+
+func (r *Router) SearchRepositories(ctx context.Context, query string) ([]models.Repository, error) {
+	return r.collect(func(p Provider) ([]models.Repository, error) {
+		return p.SearchRepositories(ctx, query)
+	})
+}
+
+func (r *Router) collect(list func(Provider) ([]models.Repository, error)) ([]models.Repository, error) {
+	var repos []models.Repository
+	var errs []error
+	for _, host := range r.hosts {
+		found, err := list(r.providers[host])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", hostLabel(host), err))
+			continue
+		}
+		for _, repo := range found {
+			repo.Host = host
+			repos = append(repos, repo)
+		}
+	}
+
+	switch {
+	case len(errs) == 0:
+		return repos, nil
+	case len(errs) == len(r.hosts):
+		return nil, errors.Join(errs...)
+	default:
+		return repos, &PartialError{Errs: errs}
+	}
+}
+
+func (r *Router) ListBranches(ctx context.Context, repo models.Repository) ([]models.Branch, error) {
+	p, err := r.provider(repo.Host)
+	if err != nil {
+		return nil, err
+	}
+	return p.ListBranches(ctx, repo)
+}
+
+func (r *Router) GetCommits(ctx context.Context, repo models.Repository, branch string, filters models.FilterOptions, page int) (models.CommitPage, error) {
+	p, err := r.provider(repo.Host)
+	if err != nil {
+		return models.CommitPage{}, err
+	}
+	return p.GetCommits(ctx, repo, branch, filters, page)
+}
+
+func (r *Router) GetCommit(ctx context.Context, repo models.Repository, sha string) (models.Commit, error) {
+	p, err := r.provider(repo.Host)
+	if err != nil {
+		return models.Commit{}, err
+	}
+	return p.GetCommit(ctx, repo, sha)
+}
+
+func (r *Router) GetHistories(ctx context.Context, refs []HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error) {
+	byHost := make(map[string][]int)
+	for i, ref := range refs {
+		byHost[ref.Repository.Host] = append(byHost[ref.Repository.Host], i)
+	}
+
+	results := make([]models.RepoCommits, len(refs))
+	for host, indexes := range byHost {
+		hostRefs := make([]HistoryRef, len(indexes))
+		for j, i := range indexes {
+			hostRefs[j] = refs[i]
+		}
+
+		hostResults, err := r.histories(ctx, host, hostRefs, filters)
+		for j, i := range indexes {
+			if err != nil {
+				results[i] = FailedHistory(refs[i], err)
+				continue
+			}
+			results[i] = hostResults[j]
+		}
+	}
+	return results, nil
+}
+
+func (r *Router) histories(ctx context.Context, host string, refs []HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error) {
+	p, err := r.provider(host)
+	if err != nil {
+		return nil, err
+	}
+	return p.GetHistories(ctx, refs, filters)
+}
+
+func (r *Router) Compare(ctx context.Context, repo models.Repository, base, head string) (models.Comparison, error) {
+	p, err := r.provider(repo.Host)
+	if err != nil {
+		return models.Comparison{}, err
+	}
+	return p.Compare(ctx, repo, base, head)
+}
+
+func (r *Router) RateLimit() models.RateLimit {
+	var lowest models.RateLimit
+	for _, host := range r.hosts {
+		limit := r.providers[host].RateLimit()
+		if !limit.Known() {
+			continue
+		}
+		if !lowest.Known() || limit.Remaining*lowest.Limit < lowest.Remaining*limit.Limit {
+			lowest = limit
+		}
+	}
+	return lowest
+}
+
+func (r *Router) splitHost(id string) (string, string) {
+	if host, name, ok := strings.Cut(id, "/"); ok && host != "" {
+		if _, registered := r.providers[host]; registered {
+			return host, name
+		}
+	}
+	if _, ok := r.providers[""]; ok || len(r.hosts) == 0 {
+		return "", id
+	}
+	return r.hosts[0], id
+}
+
+func (r *Router) provider(host string) (Provider, error) {
+	p, ok := r.providers[host]
+	if !ok {
+		return nil, fmt.Errorf("no provider configured for %s: %w", hostLabel(host), ErrNotFound)
+	}
+	return p, nil
+}
+
+func hostLabel(host string) string {
+	if host == "" {
+		return "github.com"
+	}
+	return host
+}
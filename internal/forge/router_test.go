@@ -0,0 +1,206 @@
+package forge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+type fakeProvider struct {
+	repos    []models.Repository
+	branches []models.Branch
+	err      error
+	limit    models.RateLimit
+	requests [][]HistoryRef
+	ahead    map[string][]models.Commit
+	compared []string
+}
+
+func (f *fakeProvider) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	return f.repos, f.err
+}
+
+func (f *fakeProvider) GetRepository(ctx context.Context, nameWithOwner string) (models.Repository, error) {
+	for _, repo := range f.repos {
+		if repo.NameWithOwner == nameWithOwner {
+			return repo, nil
+		}
+	}
+	return models.Repository{}, ErrNotFound
+}
+
+func (f *fakeProvider) SearchRepositories(ctx context.Context, query string) ([]models.Repository, error) {
+	return f.repos, f.err
+}
+
+func (f *fakeProvider) ListBranches(ctx context.Context, repo models.Repository) ([]models.Branch, error) {
+	if f.branches != nil {
+		return f.branches, f.err
+	}
+	return []models.Branch{{Name: repo.NameWithOwner}}, f.err
+}
+
+func (f *fakeProvider) GetCommits(ctx context.Context, repo models.Repository, branch string, filters models.FilterOptions, page int) (models.CommitPage, error) {
+	return models.CommitPage{Page: page}, f.err
+}
+
+func (f *fakeProvider) GetCommit(ctx context.Context, repo models.Repository, sha string) (models.Commit, error) {
+	return models.Commit{SHA: sha, Message: repo.ID(), Stats: &models.CommitStats{}}, f.err
+}
+
+func (f *fakeProvider) GetHistories(ctx context.Context, refs []HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error) {
+	f.requests = append(f.requests, refs)
+	if f.err != nil {
+		return nil, f.err
+	}
+	results := make([]models.RepoCommits, len(refs))
+	for i, ref := range refs {
+		results[i] = models.RepoCommits{Repository: ref.Repository, Commits: []models.Commit{{SHA: ref.Repository.ID()}}}
+	}
+	return results, nil
+}
+
+func (f *fakeProvider) Compare(ctx context.Context, repo models.Repository, base, head string) (models.Comparison, error) {
+	f.compared = append(f.compared, head)
+	if f.ahead != nil {
+		return models.Comparison{Base: base, Head: head, Commits: f.ahead[head]}, f.err
+	}
+	return models.Comparison{Base: base, Head: head, Commits: []models.Commit{{SHA: repo.ID()}}}, f.err
+}
+
+func (f *fakeProvider) RateLimit() models.RateLimit {
+	return f.limit
+}
+
+func TestRouterListRepositories(t *testing.T) {
+	router := NewRouter()
+	router.Register("", &fakeProvider{repos: []models.Repository{{NameWithOwner: "acme/api"}}})
+	router.Register("gitlab.example.com", &fakeProvider{repos: []models.Repository{{NameWithOwner: "acme/api"}}})
+
+	repos, err := router.ListRepositories(context.Background())
+	if err != nil {
+		t.Fatalf("ListRepositories() error: %v", err)
+	}
+	if len(repos) != 2 || repos[0].ID() != "acme/api" || repos[1].ID() != "gitlab.example.com/acme/api" {
+		t.Errorf("repos = %+v", repos)
+	}
+
+	router.Register("gitea.example.com", &fakeProvider{err: ErrUnauthorized})
+	repos, err = router.ListRepositories(context.Background())
+	var partial *PartialError
+	if !errors.As(err, &partial) || !errors.Is(err, ErrUnauthorized) || len(repos) != 2 {
+		t.Errorf("ListRepositories() = %d repos, %v, want the other hosts and a partial error", len(repos), err)
+	}
+	if err.Error() != "gitea.example.com: unauthorized" {
+		t.Errorf("error = %q", err)
+	}
+
+	failing := NewRouter()
+	failing.Register("", &fakeProvider{err: ErrForbidden})
+	if repos, err := failing.ListRepositories(context.Background()); repos != nil || errors.As(err, &partial) || !errors.Is(err, ErrForbidden) {
+		t.Errorf("ListRepositories() = %v, %v, want a plain error when every host fails", repos, err)
+	}
+}
+
+func TestRouterRoutesByHost(t *testing.T) {
+	router := NewRouter()
+	router.Register("", &fakeProvider{})
+
+	branches, err := router.ListBranches(context.Background(), models.Repository{NameWithOwner: "acme/api"})
+	if err != nil || len(branches) != 1 {
+		t.Errorf("ListBranches() = %v, %v", branches, err)
+	}
+
+	_, err = router.GetCommits(context.Background(), models.Repository{NameWithOwner: "acme/api", Host: "unknown.example.com"}, "", models.FilterOptions{}, 1)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetCommits() error = %v, want ErrNotFound", err)
+	}
+
+	router.Register("gitlab.example.com", &fakeProvider{})
+	commit, err := router.GetCommit(context.Background(), models.Repository{NameWithOwner: "acme/api", Host: "gitlab.example.com"}, "abc1234")
+	if err != nil || commit.SHA != "abc1234" || commit.Message != "gitlab.example.com/acme/api" {
+		t.Errorf("GetCommit() = %+v, %v", commit, err)
+	}
+	comparison, err := router.Compare(context.Background(), models.Repository{NameWithOwner: "acme/api", Host: "gitlab.example.com"}, "main", "release/1.2")
+	if err != nil || comparison.Spec() != "main...release/1.2" || comparison.Commits[0].SHA != "gitlab.example.com/acme/api" {
+		t.Errorf("Compare() = %+v, %v", comparison, err)
+	}
+}
+
+func TestRouterGetHistories(t *testing.T) {
+	github := &fakeProvider{}
+	gitlab := &fakeProvider{}
+	gitea := &fakeProvider{err: ErrForbidden}
+	router := NewRouter()
+	router.Register("", github)
+	router.Register("gitlab.example.com", gitlab)
+	router.Register("gitea.example.com", gitea)
+
+	refs := []HistoryRef{
+		{Repository: models.Repository{NameWithOwner: "acme/api", Host: "gitlab.example.com"}},
+		{Repository: models.Repository{NameWithOwner: "acme/web"}},
+		{Repository: models.Repository{NameWithOwner: "acme/infra", Host: "gitea.example.com"}},
+		{Repository: models.Repository{NameWithOwner: "acme/docs", Host: "gitlab.example.com"}},
+	}
+	results, err := router.GetHistories(context.Background(), refs, models.FilterOptions{})
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+
+	if len(github.requests) != 1 || len(gitlab.requests) != 1 || len(gitlab.requests[0]) != 2 {
+		t.Errorf("requests: github %v, gitlab %v", github.requests, gitlab.requests)
+	}
+	for i, want := range []string{"gitlab.example.com/acme/api", "acme/web", "", "gitlab.example.com/acme/docs"} {
+		if want == "" {
+			if !errors.Is(results[i].Err, ErrForbidden) {
+				t.Errorf("results[%d].Err = %v, want ErrForbidden", i, results[i].Err)
+			}
+			continue
+		}
+		if len(results[i].Commits) != 1 || results[i].Commits[0].SHA != want {
+			t.Errorf("results[%d] = %+v, want commits from %s", i, results[i], want)
+		}
+	}
+}
+
+func TestRouterGetRepository(t *testing.T) {
+	router := NewRouter()
+	router.Register("", &fakeProvider{repos: []models.Repository{{NameWithOwner: "golang/go"}}})
+	router.Register("gitlab.example.com", &fakeProvider{repos: []models.Repository{{NameWithOwner: "group/sub/project"}}})
+
+	tests := []struct {
+		name     string
+		id       string
+		expected string
+		wantErr  bool
+	}{
+		{"defaultHost", "golang/go", "golang/go", false},
+		{"hostPrefix", "gitlab.example.com/group/sub/project", "gitlab.example.com/group/sub/project", false},
+		{"unknownOnDefaultHost", "group/sub/project", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := router.GetRepository(context.Background(), tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetRepository() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if repo.ID() != tt.expected {
+				t.Errorf("GetRepository() ID = %q, want %q", repo.ID(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestRouterRateLimit(t *testing.T) {
+	router := NewRouter()
+	router.Register("", &fakeProvider{limit: models.RateLimit{Limit: 5000, Remaining: 1000}})
+	router.Register("ghes.example.com", &fakeProvider{limit: models.RateLimit{Limit: 100, Remaining: 10}})
+	router.Register("gitlab.example.com", &fakeProvider{})
+
+	if got := router.RateLimit(); got.Limit != 100 || got.Remaining != 10 {
+		t.Errorf("RateLimit() = %+v, want the most constrained host", got)
+	}
+}
@@ -0,0 +1,475 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+	logFormat = "--format=%H%x1f%an%x1f%ae%x1f%aI%x1f%B%x1e"
+
+	maxCompareCommits = 1000
+)
+
+var ErrNotRepository = errors.New("not a git repository")
+
+type localRepo struct {
+	dir    string
+	repo   models.Repository
+	webURL string
+}
+
+type Client struct {
+	repos []localRepo
+}
+
+func Open(ctx context.Context, paths ...string) (*Client, error) {
+	c := &Client{}
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		repo, err := openRepo(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if seen[repo.repo.NameWithOwner] {
+			continue
+		}
+		seen[repo.repo.NameWithOwner] = true
+		c.repos = append(c.repos, repo)
+	}
+	return c, nil
+}
+
+func openRepo(ctx context.Context, path string) (localRepo, error) {
+	out, err := runGit(ctx, path, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return localRepo{}, fmt.Errorf("%s: %w", path, ErrNotRepository)
+	}
+	dir := strings.TrimSpace(string(out))
+
+	remote, _ := runGit(ctx, dir, "remote", "get-url", "origin")
+	nameWithOwner, webURL := parseRemote(strings.TrimSpace(string(remote)))
+	if nameWithOwner == "" {
+		nameWithOwner = "local/" + filepath.Base(dir)
+	}
+
+	repo := models.Repository{
+		Name:              filepath.Base(dir),
+		NameWithOwner:     nameWithOwner,
+		URL:               webURL,
+		DefaultBranchName: defaultBranch(ctx, dir),
+	}
+	if repo.URL == "" {
+		repo.URL = "file://" + dir
+	}
+	if out, err := runGit(ctx, dir, "log", "-1", "--format=%cI"); err == nil {
+		repo.PushedAt, _ = time.Parse(time.RFC3339, strings.TrimSpace(string(out)))
+	}
+	return localRepo{dir: dir, repo: repo, webURL: webURL}, nil
+}
+
+func defaultBranch(ctx context.Context, dir string) string {
+	if out, err := runGit(ctx, dir, "symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/")
+	}
+	if out, err := runGit(ctx, dir, "symbolic-ref", "--short", "HEAD"); err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	return ""
+}
+
+func (c *Client) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	repos := make([]models.Repository, len(c.repos))
+	for i, r := range c.repos {
+		repos[i] = r.repo
+	}
+	return repos, nil
+}
+
+func (c *Client) GetRepository(ctx context.Context, nameWithOwner string) (models.Repository, error) {
+	r, err := c.find(nameWithOwner)
+	return r.repo, err
+}
+
+func (c *Client) SearchRepositories(ctx context.Context, query string) ([]models.Repository, error) {
+	query = strings.ToLower(query)
+	var repos []models.Repository
+	for _, r := range c.repos {
+		if strings.Contains(strings.ToLower(r.repo.NameWithOwner), query) {
+			repos = append(repos, r.repo)
+		}
+	}
+	return repos, nil
+}
+
+func (c *Client) ListBranches(ctx context.Context, repo models.Repository) ([]models.Branch, error) {
+	r, err := c.find(repo.NameWithOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := runGit(ctx, r.dir, "for-each-ref", "--sort=-committerdate",
+		"--format=%(refname:short)%1f%(objectname)%1f%(committerdate:iso-strict)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	return parseBranches(string(out)), nil
+}
+
+func (c *Client) GetCommits(ctx context.Context, repo models.Repository, branch string, filters models.FilterOptions, page int) (models.CommitPage, error) {
+	r, err := c.find(repo.NameWithOwner)
+	if err != nil {
+		return models.CommitPage{}, err
+	}
+
+	page = max(page, 1)
+	commits, hasMore, err := r.log(ctx, branch, filters, (page-1)*filters.PerPage)
+	if err != nil {
+		return models.CommitPage{}, err
+	}
+
+	result := models.CommitPage{Commits: commits, Page: page, LastPage: page}
+	if hasMore {
+		result.NextPage = page + 1
+		result.LastPage = 0
+	}
+	result.TotalCount, err = r.count(ctx, branch, filters)
+	return result, err
+}
+
+func (c *Client) GetHistories(ctx context.Context, refs []forge.HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error) {
+	results := make([]models.RepoCommits, len(refs))
+	for i, ref := range refs {
+		results[i] = c.history(ctx, ref, filters)
+	}
+	return results, nil
+}
+
+func (c *Client) history(ctx context.Context, ref forge.HistoryRef, filters models.FilterOptions) models.RepoCommits {
+	rc := models.RepoCommits{Repository: ref.Repository, Branch: ref.Branch}
+
+	r, err := c.find(ref.Repository.NameWithOwner)
+	if err == nil {
+		offset, _ := strconv.Atoi(ref.Cursor)
+		rc.Commits, rc.HasMore, err = r.log(ctx, ref.Branch, filters, offset)
+		rc.Cursor = strconv.Itoa(offset + len(rc.Commits))
+	}
+	if err == nil {
+		rc.TotalCount, err = r.count(ctx, ref.Branch, filters)
+	}
+	if err != nil {
+		rc.Status = models.RepoStatusFailed
+		rc.Err = err
+	}
+	return rc
+}
+
+func (c *Client) Compare(ctx context.Context, repo models.Repository, base, head string) (models.Comparison, error) {
+	r, err := c.find(repo.NameWithOwner)
+	if err != nil {
+		return models.Comparison{}, err
+	}
+	for _, ref := range []string{base, head} {
+		if _, err := runGit(ctx, r.dir, "rev-parse", "--verify", "--quiet", ref+"^{commit}"); err != nil {
+			return models.Comparison{}, fmt.Errorf("ref %q: %w", ref, forge.ErrNotFound)
+		}
+	}
+
+	out, err := runGit(ctx, r.dir, "rev-list", "--left-right", "--count", base+"..."+head, "--")
+	if err != nil {
+		return models.Comparison{}, err
+	}
+	comparison := models.Comparison{Base: base, Head: head}
+	if _, err := fmt.Sscan(string(out), &comparison.BehindBy, &comparison.AheadBy); err != nil {
+		return models.Comparison{}, fmt.Errorf("rev-list: %w", err)
+	}
+	comparison.Status = models.CompareStatus(comparison.AheadBy, comparison.BehindBy)
+
+	if out, err = runGit(ctx, r.dir, "log", logFormat, "--max-count="+strconv.Itoa(maxCompareCommits), base+".."+head, "--"); err != nil {
+		return models.Comparison{}, err
+	}
+	comparison.Commits = parseLog(string(out), r.webURL)
+
+	comparison.Files, err = r.diff(ctx, "diff", base+"..."+head)
+	if err != nil {
+		return models.Comparison{}, err
+	}
+	return comparison, nil
+}
+
+func (c *Client) GetCommit(ctx context.Context, repo models.Repository, sha string) (models.Commit, error) {
+	r, err := c.find(repo.NameWithOwner)
+	if err != nil {
+		return models.Commit{}, err
+	}
+	if _, err := runGit(ctx, r.dir, "rev-parse", "--verify", "--quiet", sha+"^{commit}"); err != nil {
+		return models.Commit{}, fmt.Errorf("commit %s: %w", sha, forge.ErrNotFound)
+	}
+
+	out, err := runGit(ctx, r.dir, "log", "-1", logFormat, sha, "--")
+	if err != nil {
+		return models.Commit{}, err
+	}
+	commits := parseLog(string(out), r.webURL)
+	if len(commits) == 0 {
+		return models.Commit{}, fmt.Errorf("commit %s: %w", sha, forge.ErrNotFound)
+	}
+	commit := commits[0]
+
+	if out, err = runGit(ctx, r.dir, "log", "-1", "--format=%P", sha, "--"); err != nil {
+		return models.Commit{}, err
+	}
+	commit.Parents = strings.Fields(string(out))
+
+	args := []string{"diff-tree", "-r", "--root", "--no-commit-id", sha}
+	if len(commit.Parents) > 0 {
+		args = []string{"diff", commit.Parents[0], sha}
+	}
+	if commit.Files, err = r.diff(ctx, args...); err != nil {
+		return models.Commit{}, err
+	}
+	commit.Stats = &models.CommitStats{}
+	for _, f := range commit.Files {
+		commit.Stats.Additions += f.Additions
+		commit.Stats.Deletions += f.Deletions
+	}
+	return commit, nil
+}
+
+func (c *Client) RateLimit() models.RateLimit {
+	return models.RateLimit{}
+}
+
+func (c *Client) find(nameWithOwner string) (localRepo, error) {
+	for _, r := range c.repos {
+		if r.repo.NameWithOwner == nameWithOwner {
+			return r, nil
+		}
+	}
+	return localRepo{}, fmt.Errorf("%s: %w", nameWithOwner, forge.ErrNotFound)
+}
+
+func (r localRepo) log(ctx context.Context, branch string, filters models.FilterOptions, offset int) ([]models.Commit, bool, error) {
+	rev, err := r.resolve(ctx, branch)
+	if err != nil {
+		return nil, false, err
+	}
+
+	args := append([]string{"log", logFormat, "--skip=" + strconv.Itoa(offset), "--max-count=" + strconv.Itoa(filters.PerPage+1)},
+		filterArgs(filters)...)
+	out, err := runGit(ctx, r.dir, append(args, rev, "--")...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	commits := parseLog(string(out), r.webURL)
+	hasMore := len(commits) > filters.PerPage
+	return commits[:min(len(commits), filters.PerPage)], hasMore, nil
+}
+
+func (r localRepo) count(ctx context.Context, branch string, filters models.FilterOptions) (int, error) {
+	rev, err := r.resolve(ctx, branch)
+	if err != nil {
+		return 0, err
+	}
+
+	args := append([]string{"rev-list", "--count"}, filterArgs(filters)...)
+	out, err := runGit(ctx, r.dir, append(args, rev, "--")...)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(string(out)))
+}
+
+func (r localRepo) diff(ctx context.Context, args ...string) ([]models.FileChange, error) {
+	run := func(format string) (string, error) {
+		command := append([]string{args[0], format, "-z", "-M"}, args[1:]...)
+		out, err := runGit(ctx, r.dir, append(command, "--")...)
+		return string(out), err
+	}
+
+	statuses, err := run("--name-status")
+	if err != nil {
+		return nil, err
+	}
+	stats, err := run("--numstat")
+	if err != nil {
+		return nil, err
+	}
+	return parseDiff(statuses, stats), nil
+}
+
+func (r localRepo) resolve(ctx context.Context, branch string) (string, error) {
+	rev := "HEAD"
+	if branch != "" {
+		rev = "refs/heads/" + branch
+	}
+	if _, err := runGit(ctx, r.dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}"); err != nil {
+		if branch == "" {
+			return "", forge.ErrEmptyRepository
+		}
+		if _, headErr := runGit(ctx, r.dir, "rev-parse", "--verify", "--quiet", "HEAD"); headErr != nil {
+			return "", forge.ErrEmptyRepository
+		}
+		return "", fmt.Errorf("branch %q: %w", branch, forge.ErrNotFound)
+	}
+	return rev, nil
+}
+
+func filterArgs(filters models.FilterOptions) []string {
+	var args []string
+	if filters.DateFrom != "" {
+		args = append(args, "--since="+filters.DateFrom+"T00:00:00Z")
+	}
+	if filters.DateTo != "" {
+		args = append(args, "--until="+filters.DateTo+"T23:59:59Z")
+	}
+	if filters.Author != "" {
+		args = append(args, "--regexp-ignore-case", "--fixed-strings", "--author="+filters.Author)
+	}
+	return args
+}
+
+func parseLog(output, webURL string) []models.Commit {
+	var commits []models.Commit
+	for _, record := range strings.Split(output, recordSep) {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), fieldSep, 5)
+		if len(fields) < 5 {
+			continue
+		}
+		date, _ := time.Parse(time.RFC3339, fields[3])
+		commit := models.Commit{
+			SHA:     fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Date:    date,
+			Message: strings.TrimRight(fields[4], "\n"),
+		}
+		if webURL != "" {
+			commit.URL = webURL + "/commit/" + commit.SHA
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+func parseDiff(nameStatus, numstat string) []models.FileChange {
+	var files []models.FileChange
+	tokens := strings.Split(nameStatus, "\x00")
+	for i := 0; i+1 < len(tokens) && tokens[i] != ""; i += 2 {
+		file := models.FileChange{Status: diffStatus(tokens[i]), Filename: tokens[i+1]}
+		if code := tokens[i][:1]; (code == "R" || code == "C") && i+2 < len(tokens) {
+			file.PreviousFilename, file.Filename = tokens[i+1], tokens[i+2]
+			i++
+		}
+		files = append(files, file)
+	}
+
+	index := make(map[string]int, len(files))
+	for i, f := range files {
+		index[f.Filename] = i
+	}
+	tokens = strings.Split(numstat, "\x00")
+	for i := 0; i < len(tokens); i++ {
+		fields := strings.SplitN(tokens[i], "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		path := fields[2]
+		if path == "" && i+2 < len(tokens) {
+			path = tokens[i+2]
+			i += 2
+		}
+		if j, ok := index[path]; ok {
+			files[j].Additions, _ = strconv.Atoi(fields[0])
+			files[j].Deletions, _ = strconv.Atoi(fields[1])
+		}
+	}
+	return files
+}
+
+func diffStatus(code string) string {
+	switch code[:1] {
+	case "A":
+		return "added"
+	case "D":
+		return "removed"
+	case "R":
+		return "renamed"
+	case "C":
+		return "copied"
+	case "T":
+		return "changed"
+	default:
+		return "modified"
+	}
+}
+
+func parseBranches(output string) []models.Branch {
+	var branches []models.Branch
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, fieldSep)
+		if len(fields) != 3 {
+			continue
+		}
+		committedAt, _ := time.Parse(time.RFC3339, fields[2])
+		branches = append(branches, models.Branch{Name: fields[0], SHA: fields[1], CommittedAt: committedAt})
+	}
+	return branches
+}
+
+func parseRemote(remote string) (nameWithOwner, webURL string) {
+	if remote == "" {
+		return "", ""
+	}
+
+	var host, path string
+	if i := strings.Index(remote, "://"); i >= 0 {
+		rest := remote[i+3:]
+		if at := strings.LastIndex(rest, "@"); at >= 0 {
+			rest = rest[at+1:]
+		}
+		host, path, _ = strings.Cut(rest, "/")
+		host, _, _ = strings.Cut(host, ":")
+	} else if at, colon := strings.Index(remote, "@"), strings.Index(remote, ":"); colon > at {
+		host, path = remote[at+1:colon], remote[colon+1:]
+	} else {
+		return "", ""
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if host == "" || strings.Count(path, "/") < 1 {
+		return "", ""
+	}
+	return path, "https://" + host + "/" + path
+}
+
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.New(msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
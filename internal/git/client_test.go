@@ -0,0 +1,249 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+func gitCmd(t *testing.T, dir string, env []string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_CONFIG_GLOBAL=/dev/null", "GIT_CONFIG_SYSTEM=/dev/null")
+	cmd.Env = append(cmd.Env, env...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func commit(t *testing.T, dir, author, date, message string) {
+	t.Helper()
+	gitCmd(t, dir, []string{
+		"GIT_AUTHOR_NAME=" + author, "GIT_AUTHOR_EMAIL=" + author + "@example.com", "GIT_AUTHOR_DATE=" + date,
+		"GIT_COMMITTER_NAME=" + author, "GIT_COMMITTER_EMAIL=" + author + "@example.com", "GIT_COMMITTER_DATE=" + date,
+	}, "commit", "--allow-empty", "-q", "-m", message)
+}
+
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	gitCmd(t, dir, nil, "init", "-q", "-b", "main")
+	gitCmd(t, dir, nil, "remote", "add", "origin", "git@github.com:octo/api.git")
+	commit(t, dir, "alice", "2024-03-01T10:00:00Z", "first")
+	commit(t, dir, "bob", "2024-03-05T10:00:00Z", "second\n\nwith body")
+	commit(t, dir, "alice", "2024-03-10T10:00:00Z", "third")
+	gitCmd(t, dir, nil, "branch", "feature", "HEAD~1")
+	return dir
+}
+
+func TestOpenDetectsRepository(t *testing.T) {
+	dir := newTestRepo(t)
+	client, err := Open(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+
+	repos, _ := client.ListRepositories(context.Background())
+	if len(repos) != 1 {
+		t.Fatalf("repos = %+v", repos)
+	}
+	r := repos[0]
+	if r.NameWithOwner != "octo/api" || r.URL != "https://github.com/octo/api" || r.DefaultBranchName != "main" {
+		t.Errorf("repo = %+v", r)
+	}
+	if r.PushedAt.IsZero() {
+		t.Error("PushedAt should be the last commit date")
+	}
+}
+
+func TestOpenRejectsNonRepository(t *testing.T) {
+	if _, err := Open(context.Background(), t.TempDir()); !errors.Is(err, ErrNotRepository) {
+		t.Errorf("Open() error = %v, want ErrNotRepository", err)
+	}
+}
+
+func TestGetHistories(t *testing.T) {
+	client, err := Open(context.Background(), newTestRepo(t))
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	repo := models.Repository{NameWithOwner: "octo/api"}
+
+	tests := []struct {
+		name    string
+		ref     forge.HistoryRef
+		filters models.FilterOptions
+		want    []string
+		hasMore bool
+	}{
+		{"all", forge.HistoryRef{Repository: repo, Branch: "main"}, models.FilterOptions{PerPage: 10}, []string{"third", "second\n\nwith body", "first"}, false},
+		{"page", forge.HistoryRef{Repository: repo, Branch: "main"}, models.FilterOptions{PerPage: 2}, []string{"third", "second\n\nwith body"}, true},
+		{"cursor", forge.HistoryRef{Repository: repo, Branch: "main", Cursor: "2"}, models.FilterOptions{PerPage: 2}, []string{"first"}, false},
+		{"branch", forge.HistoryRef{Repository: repo, Branch: "feature"}, models.FilterOptions{PerPage: 10}, []string{"second\n\nwith body", "first"}, false},
+		{"author", forge.HistoryRef{Repository: repo, Branch: "main"}, models.FilterOptions{PerPage: 10, Author: "ALICE"}, []string{"third", "first"}, false},
+		{"dates", forge.HistoryRef{Repository: repo, Branch: "main"}, models.FilterOptions{PerPage: 10, DateFrom: "2024-03-02", DateTo: "2024-03-05"}, []string{"second\n\nwith body"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := client.GetHistories(context.Background(), []forge.HistoryRef{tt.ref}, tt.filters)
+			if err != nil {
+				t.Fatalf("GetHistories() error: %v", err)
+			}
+			rc := results[0]
+			if rc.Failed() {
+				t.Fatalf("GetHistories() failed: %v", rc.Err)
+			}
+			if len(rc.Commits) != len(tt.want) {
+				t.Fatalf("commits = %+v, want %v", rc.Commits, tt.want)
+			}
+			for i, want := range tt.want {
+				if rc.Commits[i].Message != want {
+					t.Errorf("commit %d message = %q, want %q", i, rc.Commits[i].Message, want)
+				}
+			}
+			if rc.HasMore != tt.hasMore {
+				t.Errorf("HasMore = %v, want %v", rc.HasMore, tt.hasMore)
+			}
+		})
+	}
+}
+
+func TestGetHistoriesUnknownBranch(t *testing.T) {
+	client, _ := Open(context.Background(), newTestRepo(t))
+	ref := forge.HistoryRef{Repository: models.Repository{NameWithOwner: "octo/api"}, Branch: "missing"}
+
+	results, _ := client.GetHistories(context.Background(), []forge.HistoryRef{ref}, models.FilterOptions{PerPage: 10})
+	if !errors.Is(results[0].Err, forge.ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", results[0].Err)
+	}
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "README.md", "# api\n")
+	writeFile(t, dir, "old.txt", "unchanged\n")
+	gitCmd(t, dir, nil, "add", ".")
+	commit(t, dir, "alice", "2024-03-11T10:00:00Z", "add files")
+
+	gitCmd(t, dir, nil, "checkout", "-q", "-b", "release")
+	writeFile(t, dir, "README.md", "# api\n\nRelease notes\n")
+	writeFile(t, dir, "CHANGELOG.md", "v1\n")
+	gitCmd(t, dir, nil, "mv", "old.txt", "new.txt")
+	gitCmd(t, dir, nil, "add", ".")
+	commit(t, dir, "bob", "2024-03-12T10:00:00Z", "prepare release")
+	gitCmd(t, dir, nil, "checkout", "-q", "main")
+	commit(t, dir, "alice", "2024-03-13T10:00:00Z", "hotfix")
+
+	client, _ := Open(context.Background(), dir)
+	repo := models.Repository{NameWithOwner: "octo/api"}
+	comparison, err := client.Compare(context.Background(), repo, "main", "release")
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+	if comparison.AheadBy != 1 || comparison.BehindBy != 1 || comparison.Status != models.CompareDiverged {
+		t.Errorf("comparison = %+v", comparison)
+	}
+	if len(comparison.Commits) != 1 || comparison.Commits[0].FirstLine() != "prepare release" {
+		t.Errorf("commits = %+v", comparison.Commits)
+	}
+
+	want := []models.FileChange{
+		{Filename: "CHANGELOG.md", Status: "added", Additions: 1},
+		{Filename: "README.md", Status: "modified", Additions: 2},
+		{Filename: "new.txt", PreviousFilename: "old.txt", Status: "renamed"},
+	}
+	if !slices.Equal(comparison.Files, want) {
+		t.Errorf("files = %+v, want %+v", comparison.Files, want)
+	}
+
+	if _, err := client.Compare(context.Background(), repo, "main", "missing"); !errors.Is(err, forge.ErrNotFound) {
+		t.Errorf("Compare() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetCommit(t *testing.T) {
+	dir := newTestRepo(t)
+	writeFile(t, dir, "main.go", "package main\n\nfunc main() {}\n")
+	gitCmd(t, dir, nil, "add", ".")
+	commit(t, dir, "carol", "2024-03-11T10:00:00Z", "add main")
+
+	client, _ := Open(context.Background(), dir)
+	repo := models.Repository{NameWithOwner: "octo/api"}
+	history, _ := client.GetHistories(context.Background(), []forge.HistoryRef{{Repository: repo}}, models.FilterOptions{PerPage: 10})
+	commits := history[0].Commits
+
+	detail, err := client.GetCommit(context.Background(), repo, commits[0].SHA)
+	if err != nil {
+		t.Fatalf("GetCommit() error: %v", err)
+	}
+	if detail.Author != "carol" || !slices.Equal(detail.Parents, []string{commits[1].SHA}) {
+		t.Errorf("commit = %+v", detail)
+	}
+	want := []models.FileChange{{Filename: "main.go", Status: "added", Additions: 3}}
+	if !slices.Equal(detail.Files, want) || detail.Stats == nil || detail.Stats.Additions != 3 {
+		t.Errorf("files = %+v, stats = %+v", detail.Files, detail.Stats)
+	}
+
+	root, err := client.GetCommit(context.Background(), repo, commits[len(commits)-1].SHA)
+	if err != nil || len(root.Parents) != 0 || len(root.Files) != 0 || !root.HasDetail() {
+		t.Errorf("root commit = %+v, %v", root, err)
+	}
+
+	if _, err := client.GetCommit(context.Background(), repo, "0000000"); !errors.Is(err, forge.ErrNotFound) {
+		t.Errorf("GetCommit() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListBranches(t *testing.T) {
+	client, _ := Open(context.Background(), newTestRepo(t))
+
+	branches, err := client.ListBranches(context.Background(), models.Repository{NameWithOwner: "octo/api"})
+	if err != nil {
+		t.Fatalf("ListBranches() error: %v", err)
+	}
+	if len(branches) != 2 || branches[0].Name != "main" || branches[0].SHA == "" {
+		t.Errorf("branches = %+v", branches)
+	}
+}
+
+func TestParseRemote(t *testing.T) {
+	tests := []struct {
+		remote string
+		name   string
+		url    string
+	}{
+		{"git@github.com:octo/api.git", "octo/api", "https://github.com/octo/api"},
+		{"https://github.com/octo/api.git", "octo/api", "https://github.com/octo/api"},
+		{"ssh://git@gitea.example.com:2222/team/svc.git", "team/svc", "https://gitea.example.com/team/svc"},
+		{"https://gitlab.com/group/sub/project", "group/sub/project", "https://gitlab.com/group/sub/project"},
+		{"/srv/git/api.git", "", ""},
+		{"", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.remote, func(t *testing.T) {
+			name, url := parseRemote(tt.remote)
+			if name != tt.name || url != tt.url {
+				t.Errorf("parseRemote() = %q, %q, want %q, %q", name, url, tt.name, tt.url)
+			}
+		})
+	}
+}
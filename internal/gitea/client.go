@@ -0,0 +1,349 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const (
+	perPage        = 50
+	maxPages       = 20
+	searchPageSize = 20
+)
+
+type Client struct {
+	rest *forge.RESTClient
+}
+
+type repoResponse struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	Description   string `json:"description"`
+	HTMLURL       string `json:"html_url"`
+	UpdatedAt     string `json:"updated_at"`
+	DefaultBranch string `json:"default_branch"`
+	Empty         bool   `json:"empty"`
+	Fork          bool   `json:"fork"`
+	Archived      bool   `json:"archived"`
+	Private       bool   `json:"private"`
+	Internal      bool   `json:"internal"`
+	Language      string `json:"language"`
+	StarsCount    int    `json:"stars_count"`
+}
+
+type branchResponse struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"`
+	Commit    struct {
+		ID        string `json:"id"`
+		Timestamp string `json:"timestamp"`
+	} `json:"commit"`
+}
+
+type commitResponse struct {
+	SHA     string `json:"sha"`
+	HTMLURL string `json:"html_url"`
+	Commit  struct {
+		Message string `json:"message"`
+		Author  struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+			Date  string `json:"date"`
+		} `json:"author"`
+	} `json:"commit"`
+	Files []struct {
+		Filename string `json:"filename"`
+		Status   string `json:"status"`
+	} `json:"files"`
+}
+
+type commitDetailResponse struct {
+	commitResponse
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+}
+
+type compareResponse struct {
+	TotalCommits int              `json:"total_commits"`
+	Commits      []commitResponse `json:"commits"`
+}
+
+func New(baseURL, token string) *Client {
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "token "+token)
+	}
+	return &Client{rest: forge.NewRESTClient(forge.Gitea, baseURL, header)}
+}
+
+func (c *Client) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	var repos []models.Repository
+	for page := 1; page > 0 && page <= maxPages; {
+		var response []repoResponse
+		header, err := c.rest.Get(ctx, fmt.Sprintf("user/repos?limit=%d&page=%d", perPage, page), &response)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range response {
+			repos = append(repos, mapRepository(r))
+		}
+		page = forge.ParsePageLinks(header.Get("Link")).Next
+	}
+	return repos, nil
+}
+
+func (c *Client) GetRepository(ctx context.Context, nameWithOwner string) (models.Repository, error) {
+	var response repoResponse
+	if _, err := c.rest.Get(ctx, "repos/"+nameWithOwner, &response); err != nil {
+		return models.Repository{}, fmt.Errorf("%s: %w", nameWithOwner, err)
+	}
+	return mapRepository(response), nil
+}
+
+func (c *Client) SearchRepositories(ctx context.Context, query string) ([]models.Repository, error) {
+	var response struct {
+		Data []repoResponse `json:"data"`
+	}
+	endpoint := fmt.Sprintf("repos/search?q=%s&limit=%d", url.QueryEscape(query), searchPageSize)
+	if _, err := c.rest.Get(ctx, endpoint, &response); err != nil {
+		return nil, err
+	}
+	repos := make([]models.Repository, len(response.Data))
+	for i, r := range response.Data {
+		repos[i] = mapRepository(r)
+	}
+	return repos, nil
+}
+
+func (c *Client) ListBranches(ctx context.Context, repo models.Repository) ([]models.Branch, error) {
+	var branches []models.Branch
+	for page := 1; page > 0; {
+		endpoint := fmt.Sprintf("repos/%s/branches?limit=%d&page=%d", repo.NameWithOwner, perPage, page)
+
+		var response []branchResponse
+		header, err := c.rest.Get(ctx, endpoint, &response)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range response {
+			committedAt, _ := time.Parse(time.RFC3339, b.Commit.Timestamp)
+			branches = append(branches, models.Branch{
+				Name:        b.Name,
+				SHA:         b.Commit.ID,
+				Protected:   b.Protected,
+				CommittedAt: committedAt,
+			})
+		}
+		page = forge.ParsePageLinks(header.Get("Link")).Next
+	}
+	models.SortBranchesByRecency(branches)
+	return branches, nil
+}
+
+func (c *Client) GetCommits(ctx context.Context, repo models.Repository, branch string, filters models.FilterOptions, page int) (models.CommitPage, error) {
+	result := models.CommitPage{Page: page, NextPage: page}
+	for result.NextPage > 0 {
+		var response []commitResponse
+		header, err := c.rest.Get(ctx, commitsEndpoint(repo, branch, filters, result.NextPage), &response)
+		if err != nil {
+			return models.CommitPage{}, err
+		}
+
+		links := forge.ParsePageLinks(header.Get("Link"))
+		result.Commits = append(result.Commits, filterAuthor(mapCommits(response), filters.Author)...)
+		result.NextPage = links.Next
+		if filters.Author == "" {
+			result.LastPage = links.Last
+			result.TotalCount, _ = strconv.Atoi(header.Get("X-Total-Count"))
+			break
+		}
+		if len(result.Commits) >= filters.PerPage {
+			break
+		}
+	}
+	return result, nil
+}
+
+func (c *Client) GetCommit(ctx context.Context, repo models.Repository, sha string) (models.Commit, error) {
+	var response commitDetailResponse
+	if _, err := c.rest.Get(ctx, fmt.Sprintf("repos/%s/git/commits/%s?stat=true&files=true", repo.NameWithOwner, url.PathEscape(sha)), &response); err != nil {
+		return models.Commit{}, err
+	}
+
+	commit := mapCommits([]commitResponse{response.commitResponse})[0]
+	for _, p := range response.Parents {
+		commit.Parents = append(commit.Parents, p.SHA)
+	}
+	commit.Files = changedFiles([]commitResponse{response.commitResponse})
+	commit.Stats = &models.CommitStats{Additions: response.Stats.Additions, Deletions: response.Stats.Deletions}
+	return commit, nil
+}
+
+func (c *Client) GetHistories(ctx context.Context, refs []forge.HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error) {
+	results := make([]models.RepoCommits, len(refs))
+	for i, ref := range refs {
+		if ref.Branch == "" && ref.Repository.DefaultBranchName == "" {
+			results[i] = forge.FailedHistory(ref, forge.ErrEmptyRepository)
+			continue
+		}
+
+		page, _ := strconv.Atoi(ref.Cursor)
+		result, err := c.GetCommits(ctx, ref.Repository, ref.Branch, filters, max(page, 1))
+		if err != nil {
+			results[i] = forge.FailedHistory(ref, err)
+			continue
+		}
+		results[i] = models.RepoCommits{
+			Repository: ref.Repository,
+			Branch:     ref.Branch,
+			Commits:    result.Commits,
+			HasMore:    result.HasMore(),
+			Page:       result.Page,
+			NextPage:   result.NextPage,
+			TotalCount: result.TotalCount,
+		}
+		if result.HasMore() {
+			results[i].Cursor = strconv.Itoa(result.NextPage)
+		}
+	}
+	return results, nil
+}
+
+func (c *Client) Compare(ctx context.Context, repo models.Repository, base, head string) (models.Comparison, error) {
+	var ahead, behind compareResponse
+	if _, err := c.rest.Get(ctx, fmt.Sprintf("repos/%s/compare/%s...%s", repo.NameWithOwner, base, head), &ahead); err != nil {
+		return models.Comparison{}, err
+	}
+	if _, err := c.rest.Get(ctx, fmt.Sprintf("repos/%s/compare/%s...%s", repo.NameWithOwner, head, base), &behind); err != nil {
+		return models.Comparison{}, err
+	}
+
+	commits := mapCommits(ahead.Commits)
+	slices.SortStableFunc(commits, func(a, b models.Commit) int { return b.Date.Compare(a.Date) })
+	return models.Comparison{
+		Base:     base,
+		Head:     head,
+		Status:   models.CompareStatus(ahead.TotalCommits, behind.TotalCommits),
+		AheadBy:  ahead.TotalCommits,
+		BehindBy: behind.TotalCommits,
+		Commits:  commits,
+		Files:    changedFiles(ahead.Commits),
+	}, nil
+}
+
+func (c *Client) RateLimit() models.RateLimit {
+	return models.RateLimit{}
+}
+
+func commitsEndpoint(repo models.Repository, branch string, filters models.FilterOptions, page int) string {
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(filters.PerPage))
+	query.Set("page", strconv.Itoa(page))
+	query.Set("stat", "false")
+	query.Set("verification", "false")
+	query.Set("files", "false")
+	if branch != "" {
+		query.Set("sha", branch)
+	}
+	if filters.DateFrom != "" {
+		query.Set("since", filters.DateFrom+"T00:00:00Z")
+	}
+	if filters.DateTo != "" {
+		query.Set("until", filters.DateTo+"T23:59:59Z")
+	}
+	return fmt.Sprintf("repos/%s/commits?%s", repo.NameWithOwner, query.Encode())
+}
+
+func filterAuthor(commits []models.Commit, author string) []models.Commit {
+	if author == "" {
+		return commits
+	}
+	author = strings.ToLower(author)
+
+	var filtered []models.Commit
+	for _, c := range commits {
+		if strings.Contains(strings.ToLower(c.Author), author) || strings.Contains(strings.ToLower(c.Email), author) {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+func changedFiles(commits []commitResponse) []models.FileChange {
+	sorted := slices.Clone(commits)
+	slices.SortStableFunc(sorted, func(a, b commitResponse) int {
+		dateA, _ := time.Parse(time.RFC3339, a.Commit.Author.Date)
+		dateB, _ := time.Parse(time.RFC3339, b.Commit.Author.Date)
+		return dateA.Compare(dateB)
+	})
+
+	var files []models.FileChange
+	index := make(map[string]int)
+	for _, c := range sorted {
+		for _, f := range c.Files {
+			i, seen := index[f.Filename]
+			switch {
+			case !seen:
+				index[f.Filename] = len(files)
+				files = append(files, models.FileChange{Filename: f.Filename, Status: f.Status})
+			case f.Status == "removed":
+				files[i].Status = f.Status
+			}
+		}
+	}
+	return files
+}
+
+func mapRepository(r repoResponse) models.Repository {
+	pushedAt, _ := time.Parse(time.RFC3339, r.UpdatedAt)
+	repo := models.Repository{
+		Name:              r.Name,
+		NameWithOwner:     r.FullName,
+		Description:       r.Description,
+		URL:               r.HTMLURL,
+		PushedAt:          pushedAt,
+		DefaultBranchName: r.DefaultBranch,
+		IsFork:            r.Fork,
+		IsArchived:        r.Archived,
+		IsPrivate:         r.Private,
+		PrimaryLanguage:   r.Language,
+		Stars:             r.StarsCount,
+	}
+	if r.Internal {
+		repo.Visibility = "internal"
+	}
+	if r.Empty {
+		repo.DefaultBranchName = ""
+	}
+	return repo
+}
+
+func mapCommits(response []commitResponse) []models.Commit {
+	commits := make([]models.Commit, len(response))
+	for i, c := range response {
+		date, _ := time.Parse(time.RFC3339, c.Commit.Author.Date)
+		commits[i] = models.Commit{
+			SHA:     c.SHA,
+			Message: c.Commit.Message,
+			Author:  c.Commit.Author.Name,
+			Email:   c.Commit.Author.Email,
+			Date:    date,
+			URL:     c.HTMLURL,
+		}
+	}
+	return commits
+}
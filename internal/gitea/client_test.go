@@ -0,0 +1,242 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+var terraform = models.Repository{NameWithOwner: "infra/terraform", DefaultBranchName: "main"}
+
+func newFixtureClient(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"token is required"}`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return New(server.URL+"/api/v1", "test-token")
+}
+
+func serveFixture(t *testing.T, w http.ResponseWriter, name string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	w.Write(data)
+}
+
+func TestListRepositories(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/user/repos" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		serveFixture(t, w, "repos.json")
+	})
+
+	repos, err := client.ListRepositories(context.Background())
+	if err != nil {
+		t.Fatalf("ListRepositories() error: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("repos = %+v", repos)
+	}
+	if r := repos[0]; r.NameWithOwner != "infra/terraform" || r.URL != "https://git.example.com/infra/terraform" || r.DefaultBranchName != "main" {
+		t.Errorf("repos[0] = %+v", r)
+	}
+	if repos[1].DefaultBranchName != "" {
+		t.Errorf("empty repo should have no default branch, got %q", repos[1].DefaultBranchName)
+	}
+	if r := repos[0]; !r.IsPrivate || r.PrimaryLanguage != "HCL" || r.Stars != 3 {
+		t.Errorf("repos[0] metadata = %+v", r)
+	}
+	if r := repos[1]; !r.IsFork || !r.IsArchived {
+		t.Errorf("repos[1] metadata = %+v", r)
+	}
+}
+
+func TestListBranches(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/infra/terraform/branches" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		serveFixture(t, w, "branches.json")
+	})
+
+	branches, err := client.ListBranches(context.Background(), terraform)
+	if err != nil {
+		t.Fatalf("ListBranches() error: %v", err)
+	}
+	if len(branches) != 2 || branches[0].Name != "staging" || !branches[1].Protected {
+		t.Errorf("branches = %+v", branches)
+	}
+}
+
+func TestGetHistories(t *testing.T) {
+	var query map[string][]string
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Link", `<https://git.example.com/api/v1/repos/infra/terraform/commits?limit=2&page=2>; rel="next",<https://git.example.com/api/v1/repos/infra/terraform/commits?limit=2&page=9>; rel="last"`)
+		w.Header().Set("X-Total-Count", "17")
+		serveFixture(t, w, "commits.json")
+	})
+
+	refs := []forge.HistoryRef{{Repository: terraform, Branch: "main"}}
+	results, err := client.GetHistories(context.Background(), refs, models.FilterOptions{PerPage: 2, DateTo: "2024-06-30"})
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+
+	rc := results[0]
+	if rc.Failed() || len(rc.Commits) != 2 || !rc.HasMore || rc.Cursor != "2" || rc.TotalCount != 17 {
+		t.Fatalf("result = %+v", rc)
+	}
+	if c := rc.Commits[1]; c.Author != "Ana Costa" || c.FirstLine() != "Add VPC peering module" || c.URL == "" {
+		t.Errorf("commit = %+v", c)
+	}
+	for key, want := range map[string]string{"sha": "main", "until": "2024-06-30T23:59:59Z", "limit": "2"} {
+		if got := query[key]; len(got) != 1 || got[0] != want {
+			t.Errorf("query %s = %v, want %s", key, got, want)
+		}
+	}
+}
+
+func TestGetHistoriesFiltersAuthorLocally(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "2")
+		serveFixture(t, w, "commits.json")
+	})
+
+	refs := []forge.HistoryRef{{Repository: terraform, Branch: "main"}}
+	results, _ := client.GetHistories(context.Background(), refs, models.FilterOptions{PerPage: 10, Author: "LEE"})
+
+	if rc := results[0]; len(rc.Commits) != 1 || rc.Commits[0].Author != "Lee Park" || rc.TotalCount != 0 {
+		t.Errorf("result = %+v", rc)
+	}
+}
+
+func TestGetHistoriesFillsAuthorPages(t *testing.T) {
+	var pages []string
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		w.Header().Set("X-Total-Count", "6")
+		next := map[string]string{"1": "2", "2": "3"}[page]
+		w.Header().Set("Link", `<https://git.example.com/api/v1/repos/infra/terraform/commits?limit=2&page=`+next+`>; rel="next"`)
+		serveFixture(t, w, "commits.json")
+	})
+
+	refs := []forge.HistoryRef{{Repository: terraform, Branch: "main"}}
+	results, _ := client.GetHistories(context.Background(), refs, models.FilterOptions{PerPage: 2, Author: "lee"})
+
+	rc := results[0]
+	if !slices.Equal(pages, []string{"1", "2"}) {
+		t.Errorf("pages = %v, want [1 2]", pages)
+	}
+	if len(rc.Commits) != 2 || rc.TotalCount != 0 || rc.Cursor != "3" {
+		t.Errorf("result = %+v", rc)
+	}
+}
+
+func TestGetHistoriesEmptyRepository(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"message":"Git Repository is empty."}`))
+	})
+
+	refs := []forge.HistoryRef{{Repository: terraform, Branch: "main"}}
+	results, _ := client.GetHistories(context.Background(), refs, models.FilterOptions{PerPage: 10})
+
+	if !errors.Is(results[0].Err, forge.ErrEmptyRepository) {
+		t.Errorf("error = %v, want ErrEmptyRepository", results[0].Err)
+	}
+}
+
+func TestSearchRepositories(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/search" || r.URL.Query().Get("q") != "terra" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{"ok": true, "data": [{"name": "terraform", "full_name": "infra/terraform", "default_branch": "main"}]}`))
+	})
+
+	repos, err := client.SearchRepositories(context.Background(), "terra")
+	if err != nil || len(repos) != 1 || repos[0].NameWithOwner != "infra/terraform" {
+		t.Errorf("SearchRepositories() = %+v, %v", repos, err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/repos/infra/terraform/compare/main...release/2024.06":
+			serveFixture(t, w, "compare.json")
+		case "/api/v1/repos/infra/terraform/compare/release/2024.06...main":
+			w.Write([]byte(`{"total_commits": 0, "commits": []}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	comparison, err := client.Compare(context.Background(), terraform, "main", "release/2024.06")
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+	if comparison.AheadBy != 2 || comparison.BehindBy != 0 || comparison.Status != models.CompareAhead {
+		t.Errorf("comparison = %+v", comparison)
+	}
+	if len(comparison.Commits) != 2 || comparison.Commits[0].Author != "Lee Park" {
+		t.Errorf("commits = %+v, want newest first", comparison.Commits)
+	}
+
+	want := []models.FileChange{
+		{Filename: "modules/peering/main.tf", Status: "added"},
+		{Filename: "modules/peering/legacy.tf", Status: "removed"},
+		{Filename: "versions.tf", Status: "modified"},
+	}
+	if !slices.Equal(comparison.Files, want) {
+		t.Errorf("files = %+v, want %+v", comparison.Files, want)
+	}
+}
+
+func TestGetCommit(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/infra/terraform/git/commits/3e1a6f0b" || r.URL.Query().Get("files") != "true" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		w.Write([]byte(`{
+			"sha": "3e1a6f0b",
+			"commit": {"message": "Pin provider versions\n", "author": {"name": "Lee Park", "date": "2024-06-12T08:45:10+02:00"}},
+			"parents": [{"sha": "0f9e8d7c"}],
+			"stats": {"total": 6, "additions": 4, "deletions": 2},
+			"files": [{"filename": "versions.tf", "status": "modified"}]
+		}`))
+	})
+
+	commit, err := client.GetCommit(context.Background(), terraform, "3e1a6f0b")
+	if err != nil {
+		t.Fatalf("GetCommit() error: %v", err)
+	}
+	if commit.Author != "Lee Park" || !slices.Equal(commit.Parents, []string{"0f9e8d7c"}) {
+		t.Errorf("commit = %+v", commit)
+	}
+	if !commit.HasDetail() || commit.Stats.Additions != 4 || commit.Stats.Deletions != 2 {
+		t.Errorf("stats = %+v", commit.Stats)
+	}
+	if len(commit.Files) != 1 || commit.Files[0].Filename != "versions.tf" {
+		t.Errorf("files = %+v", commit.Files)
+	}
+}
@@ -0,0 +1,84 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const branchesPerPage = 100
+
+const branchesQuery = `query($owner: String!, $name: String!, $first: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    refs(refPrefix: "refs/heads/", first: $first, after: $after, orderBy: {field: TAG_COMMIT_DATE, direction: DESC}) {
+      nodes {
+        name
+        branchProtectionRule { id }
+        target { oid ... on Commit { committedDate } }
+      }
+      pageInfo { hasNextPage endCursor }
+    }
+  }
+}`
+
+type branchesResponse struct {
+	Repository struct {
+		Refs struct {
+			Nodes    []branchNode `json:"nodes"`
+			PageInfo pageInfo     `json:"pageInfo"`
+		} `json:"refs"`
+	} `json:"repository"`
+}
+
+type branchNode struct {
+	Name                 string `json:"name"`
+	BranchProtectionRule *struct {
+		ID string `json:"id"`
+	} `json:"branchProtectionRule"`
+	Target struct {
+		OID           string `json:"oid"`
+		CommittedDate string `json:"committedDate"`
+	} `json:"target"`
+}
+
+func (c *apiClient) ListBranches(ctx context.Context, repo models.Repository) ([]models.Branch, error) {
+	var branches []models.Branch
+	variables := map[string]any{"owner": repo.Owner(), "name": repo.RepoName(), "first": branchesPerPage}
+
+	for {
+		var response branchesResponse
+		err := queryGraphQL(ctx, c.transport, branchesQuery, variables, &response)
+
+		var gqlErrs graphQLErrors
+		if err != nil && (!errors.As(err, &gqlErrs) || len(gqlErrs.exceptField("branchProtectionRule")) > 0) {
+			return nil, err
+		}
+
+		refs := response.Repository.Refs
+		branches = append(branches, mapBranches(refs.Nodes)...)
+		if !refs.PageInfo.HasNextPage {
+			return branches, nil
+		}
+		variables["after"] = refs.PageInfo.EndCursor
+	}
+}
+
+func mapBranches(nodes []branchNode) []models.Branch {
+	branches := make([]models.Branch, len(nodes))
+	for i, n := range nodes {
+		branches[i] = mapBranch(n)
+	}
+	return branches
+}
+
+func mapBranch(n branchNode) models.Branch {
+	committedAt, _ := time.Parse(time.RFC3339, n.Target.CommittedDate)
+	return models.Branch{
+		Name:        n.Name,
+		SHA:         n.Target.OID,
+		Protected:   n.BranchProtectionRule != nil,
+		CommittedAt: committedAt,
+	}
+}
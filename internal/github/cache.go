@@ -0,0 +1,121 @@
+package github
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/cache"
+)
+
+const DefaultCacheTTL = time.Minute
+
+type cacheEntry struct {
+	Status       int         `json:"status"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	ETag         string      `json:"etag"`
+	LastModified string      `json:"lastModified"`
+	StoredAt     time.Time   `json:"storedAt"`
+}
+
+type cacheTransport struct {
+	next      transport
+	store     *cache.Store
+	namespace string
+	ttl       time.Duration
+	now       func() time.Time
+}
+
+func newCacheTransport(next transport, store *cache.Store, namespace string, ttl time.Duration) *cacheTransport {
+	return &cacheTransport{
+		next:      next,
+		store:     store,
+		namespace: namespace,
+		ttl:       ttl,
+		now:       time.Now,
+	}
+}
+
+func (t *cacheTransport) do(ctx context.Context, req request) (*response, error) {
+	key, ok := t.key(req)
+	if !ok {
+		return t.next.do(ctx, req)
+	}
+
+	var entry cacheEntry
+	cached, _ := t.store.Get(key, &entry)
+	if cached && t.fresh(entry) {
+		return entry.response(), nil
+	}
+	if cached {
+		req = conditional(req, entry)
+	}
+
+	resp, err := t.next.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.status == http.StatusNotModified && cached:
+		entry.StoredAt = t.now()
+		t.store.Put(key, entry)
+		return entry.response(), nil
+	case resp.status == http.StatusOK:
+		t.store.Put(key, newCacheEntry(resp, t.now()))
+	}
+	return resp, nil
+}
+
+func (t *cacheTransport) key(req request) (string, bool) {
+	switch {
+	case req.method == http.MethodGet:
+		return t.namespace + " GET " + req.endpoint, true
+	case req.method == http.MethodPost && req.endpoint == graphQLEndpoint:
+		sum := sha256.Sum256(req.body)
+		return t.namespace + " POST " + req.endpoint + " " + hex.EncodeToString(sum[:]), true
+	default:
+		return "", false
+	}
+}
+
+func (t *cacheTransport) fresh(entry cacheEntry) bool {
+	return t.ttl > 0 && t.now().Sub(entry.StoredAt) < t.ttl
+}
+
+func conditional(req request, entry cacheEntry) request {
+	if entry.ETag == "" && entry.LastModified == "" {
+		return req
+	}
+
+	header := req.header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if entry.ETag != "" {
+		header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		header.Set("If-Modified-Since", entry.LastModified)
+	}
+	req.header = header
+	return req
+}
+
+func newCacheEntry(resp *response, now time.Time) cacheEntry {
+	return cacheEntry{
+		Status:       resp.status,
+		Header:       resp.header,
+		Body:         resp.body,
+		ETag:         resp.header.Get("ETag"),
+		LastModified: resp.header.Get("Last-Modified"),
+		StoredAt:     now,
+	}
+}
+
+func (e cacheEntry) response() *response {
+	return &response{status: e.Status, header: e.Header, body: e.Body}
+}
@@ -0,0 +1,114 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/cache"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const cachedReposBody = `[{"name": "api", "full_name": "octo/api", "default_branch": "main"}]`
+
+func TestCacheServesNotModifiedFromCache(t *testing.T) {
+	var requests int
+	var ifNoneMatch string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		ifNoneMatch = r.Header.Get("If-None-Match")
+		if ifNoneMatch == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(cachedReposBody))
+	}, WithCache(cache.New(t.TempDir()), 0), WithSources(models.SourceOwner))
+
+	for range 2 {
+		repos, err := client.ListRepositories(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(repos) != 1 || repos[0].NameWithOwner != "octo/api" {
+			t.Fatalf("repos = %+v, want octo/api", repos)
+		}
+	}
+
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+	if ifNoneMatch != `"v1"` {
+		t.Errorf("If-None-Match = %q, want %q", ifNoneMatch, `"v1"`)
+	}
+}
+
+func TestCacheServesFreshEntriesWithoutRequest(t *testing.T) {
+	var requests int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(cachedReposBody))
+	}, WithCache(cache.New(t.TempDir()), time.Hour), WithSources(models.SourceOwner))
+
+	for range 2 {
+		if _, err := client.ListRepositories(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1", requests)
+	}
+}
+
+func TestCacheSkipsErrorResponses(t *testing.T) {
+	store := cache.New(t.TempDir())
+	next := &fakeTransport{responses: []*response{
+		{status: http.StatusNotFound, header: http.Header{}},
+		{status: http.StatusOK, header: http.Header{}, body: []byte(`[]`)},
+	}}
+	ct := newCacheTransport(next, store, "test", time.Hour)
+	req := request{method: http.MethodGet, endpoint: "repos/octo/api"}
+
+	for _, want := range []int{http.StatusNotFound, http.StatusOK, http.StatusOK} {
+		resp, err := ct.do(context.Background(), req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.status != want {
+			t.Errorf("status = %d, want %d", resp.status, want)
+		}
+	}
+	if next.requests != 2 {
+		t.Errorf("requests = %d, want 2", next.requests)
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	ct := newCacheTransport(nil, nil, "https://api.github.com", time.Minute)
+
+	tests := []struct {
+		name  string
+		req   request
+		found bool
+	}{
+		{"get", request{method: http.MethodGet, endpoint: "user/repos"}, true},
+		{"graphql", request{method: http.MethodPost, endpoint: graphQLEndpoint, body: []byte(`{}`)}, true},
+		{"other post", request{method: http.MethodPost, endpoint: "repos/octo/api/issues"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := ct.key(tt.req); ok != tt.found {
+				t.Errorf("key ok = %v, want %v", ok, tt.found)
+			}
+		})
+	}
+
+	a, _ := ct.key(request{method: http.MethodPost, endpoint: graphQLEndpoint, body: []byte(`{"a":1}`)})
+	b, _ := ct.key(request{method: http.MethodPost, endpoint: graphQLEndpoint, body: []byte(`{"a":2}`)})
+	if a == b {
+		t.Error("graphql keys should differ by body")
+	}
+}
@@ -1,10 +1,20 @@
 package github
 
 import (
-	"bytes"
-	"encoding/json"
 	"errors"
+	"os"
 	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/cache"
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const (
+	DefaultHost    = "github.com"
+	DefaultBaseURL = "https://api.github.com"
 )
 
 var (
@@ -12,6 +22,100 @@ var (
 	ErrGHNotAuthenticated = errors.New("gh CLI is not authenticated")
 )
 
+type apiClient struct {
+	transport transport
+	limits    *rateLimitTransport
+	sources   []models.RepoSource
+	orgs      []string
+}
+
+type Option func(*clientConfig)
+
+type clientConfig struct {
+	cache    *cache.Store
+	cacheTTL time.Duration
+	sources  []models.RepoSource
+	orgs     []string
+}
+
+func WithCache(store *cache.Store, ttl time.Duration) Option {
+	return func(c *clientConfig) {
+		c.cache = store
+		c.cacheTTL = ttl
+	}
+}
+
+func WithSources(sources ...models.RepoSource) Option {
+	return func(c *clientConfig) {
+		c.sources = sources
+	}
+}
+
+func WithOrgs(orgs ...string) Option {
+	return func(c *clientConfig) {
+		c.orgs = orgs
+	}
+}
+
+func NewRESTClient(baseURL, token string, opts ...Option) forge.Provider {
+	return newAPIClient(newHTTPTransport(baseURL, token), baseURL, opts)
+}
+
+func NewGHClient(hostname string, opts ...Option) forge.Provider {
+	namespace := "gh"
+	if !IsDefaultHost(hostname) {
+		namespace += ":" + hostname
+	}
+	return newAPIClient(ghTransport{hostname: hostname}, namespace, opts)
+}
+
+func newAPIClient(t transport, namespace string, opts []Option) *apiClient {
+	config := clientConfig{sources: models.RepoSources}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	limits := newRateLimitTransport(t)
+	client := &apiClient{transport: limits, limits: limits, sources: config.sources, orgs: config.orgs}
+	if config.cache != nil {
+		client.transport = newCacheTransport(limits, config.cache, namespace, config.cacheTTL)
+	}
+	return client
+}
+
+func (c *apiClient) RateLimit() models.RateLimit {
+	return c.limits.RateLimit()
+}
+
+func IsDefaultHost(hostname string) bool {
+	return hostname == "" || strings.EqualFold(hostname, DefaultHost)
+}
+
+func BaseURL(hostname string) string {
+	if IsDefaultHost(hostname) {
+		return DefaultBaseURL
+	}
+	return "https://" + hostname + "/api/v3"
+}
+
+func Token(hostname string) string {
+	envs := []string{"GH_TOKEN", "GITHUB_TOKEN"}
+	if !IsDefaultHost(hostname) {
+		envs = []string{"GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN"}
+	}
+	for _, env := range envs {
+		if token := os.Getenv(env); token != "" {
+			return token
+		}
+	}
+
+	output, err := exec.Command("gh", hostArgs(hostname, "auth", "token")...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 func CheckGHInstalled() error {
 	if err := exec.Command("gh", "--version").Run(); err != nil {
 		return ErrGHNotInstalled
@@ -19,29 +123,16 @@ func CheckGHInstalled() error {
 	return nil
 }
 
-func CheckGHAuthenticated() error {
-	if err := exec.Command("gh", "auth", "status").Run(); err != nil {
+func CheckGHAuthenticated(hostname string) error {
+	if err := exec.Command("gh", hostArgs(hostname, "auth", "status")...).Run(); err != nil {
 		return ErrGHNotAuthenticated
 	}
 	return nil
 }
 
-func runGH(args ...string) ([]byte, error) {
-	cmd := exec.Command("gh", args...)
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return nil, errors.New(stderr.String())
-	}
-	return stdout.Bytes(), nil
-}
-
-func runGHWithJSON(result any, args ...string) error {
-	output, err := runGH(args...)
-	if err != nil {
-		return err
+func hostArgs(hostname string, args ...string) []string {
+	if hostname == "" {
+		return args
 	}
-	return json.Unmarshal(output, result)
+	return append(args, "--hostname", hostname)
 }
@@ -0,0 +1,423 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) forge.Provider {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return NewRESTClient(server.URL, "test-token", opts...)
+}
+
+func TestRESTClientSendsToken(t *testing.T) {
+	var auth string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		w.Write([]byte(`[]`))
+	})
+
+	if _, err := client.ListRepositories(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if auth != "Bearer test-token" {
+		t.Errorf("Authorization = %q, want %q", auth, "Bearer test-token")
+	}
+}
+
+func TestRESTClientListBranches(t *testing.T) {
+	var requests int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/graphql" {
+			t.Errorf("request = %s %s, want POST /graphql", r.Method, r.URL.Path)
+		}
+
+		var body graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+
+		requests++
+		if body.Variables["after"] == nil {
+			w.Write([]byte(`{"data": {"repository": {"refs": {
+				"nodes": [{"name": "dev", "branchProtectionRule": null, "target": {"oid": "bbb", "committedDate": "2024-06-20T10:00:00Z"}}],
+				"pageInfo": {"hasNextPage": true, "endCursor": "cursor1"}
+			}}}}`))
+			return
+		}
+		w.Write([]byte(`{"data": {"repository": {"refs": {
+			"nodes": [{"name": "main", "branchProtectionRule": {"id": "rule"}, "target": {"oid": "aaa", "committedDate": "2024-06-15T14:30:00Z"}}],
+			"pageInfo": {"hasNextPage": false, "endCursor": "cursor2"}
+		}}}}`))
+	})
+
+	branches, err := client.ListBranches(context.Background(), models.Repository{NameWithOwner: "owner/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2", requests)
+	}
+	if len(branches) != 2 {
+		t.Fatalf("got %d branches, want 2", len(branches))
+	}
+	if branches[0].Name != "dev" || branches[0].SHA != "bbb" || branches[0].Protected {
+		t.Errorf("branches[0] = %+v", branches[0])
+	}
+	if branches[1].Name != "main" || !branches[1].Protected || branches[1].CommittedAt.IsZero() {
+		t.Errorf("branches[1] = %+v", branches[1])
+	}
+}
+
+func TestRESTClientListBranchesWithoutProtectionAccess(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"data": {"repository": {"refs": {
+				"nodes": [{"name": "main", "branchProtectionRule": null, "target": {"oid": "aaa", "committedDate": "2024-06-15T14:30:00Z"}}],
+				"pageInfo": {"hasNextPage": false, "endCursor": "cursor1"}
+			}}},
+			"errors": [{"type": "FORBIDDEN", "message": "Resource not accessible by integration", "path": ["repository", "refs", "nodes", 0, "branchProtectionRule"]}]
+		}`))
+	})
+
+	branches, err := client.ListBranches(context.Background(), models.Repository{NameWithOwner: "owner/repo"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(branches) != 1 || branches[0].Name != "main" || branches[0].Protected {
+		t.Errorf("branches = %+v", branches)
+	}
+}
+
+func TestRESTClientGraphQLError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": null, "errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a Repository"}]}`))
+	})
+
+	_, err := client.ListBranches(context.Background(), models.Repository{NameWithOwner: "owner/missing"})
+	if err == nil || err.Error() != "Could not resolve to a Repository" {
+		t.Errorf("error = %v, want %q", err, "Could not resolve to a Repository")
+	}
+}
+
+func TestRESTClientGetCommits(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("sha"); got != "main" {
+			t.Errorf("sha = %q, want %q", got, "main")
+		}
+		w.Write([]byte(`[{
+			"sha": "abc1234567",
+			"commit": {"message": "Fix bug", "author": {"name": "John", "email": "john@example.com", "date": "2024-06-15T14:30:00Z"}},
+			"author": {"login": "john-doe"},
+			"html_url": "https://github.com/owner/repo/commit/abc1234567"
+		}]`))
+	})
+
+	page, err := client.GetCommits(context.Background(), models.Repository{NameWithOwner: "owner/repo"}, "main", models.FilterOptions{PerPage: 50}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.HasMore() {
+		t.Error("HasMore() = true, want false")
+	}
+	if page.TotalCount != 1 {
+		t.Errorf("TotalCount = %d, want 1", page.TotalCount)
+	}
+	if len(page.Commits) != 1 {
+		t.Fatalf("got %d commits, want 1", len(page.Commits))
+	}
+	if page.Commits[0].Author != "John" || page.Commits[0].Login != "john-doe" || page.Commits[0].Message != "Fix bug" {
+		t.Errorf("commit = %+v", page.Commits[0])
+	}
+}
+
+func TestRESTClientGetCommitsPagination(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		base := "<http://" + r.Host + r.URL.Path
+		if r.URL.Query().Get("per_page") == "1" {
+			w.Header().Set("Link", base+`?per_page=1&page=2>; rel="next", `+base+`?per_page=1&page=1234>; rel="last"`)
+			w.Write([]byte(`[{"sha": "a"}]`))
+			return
+		}
+		w.Header().Set("Link", base+`?per_page=2&page=2>; rel="next", `+base+`?per_page=2&page=617>; rel="last"`)
+		w.Write([]byte(`[{"sha": "a"}, {"sha": "b"}]`))
+	})
+
+	page, err := client.GetCommits(context.Background(), models.Repository{NameWithOwner: "owner/repo"}, "", models.FilterOptions{PerPage: 2}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.NextPage != 2 {
+		t.Errorf("NextPage = %d, want 2", page.NextPage)
+	}
+	if page.LastPage != 617 {
+		t.Errorf("LastPage = %d, want 617", page.LastPage)
+	}
+	if page.TotalCount != 1234 {
+		t.Errorf("TotalCount = %d, want 1234", page.TotalCount)
+	}
+}
+
+func TestRESTClientGetCommitsExactlyOnePage(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"sha": "a"}, {"sha": "b"}]`))
+	})
+
+	page, err := client.GetCommits(context.Background(), models.Repository{NameWithOwner: "owner/repo"}, "", models.FilterOptions{PerPage: 2}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if page.HasMore() {
+		t.Error("HasMore() = true, want false for a full page without a next link")
+	}
+}
+
+func TestRESTClientGetCommit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/commits/abc1234" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"sha": "abc1234",
+			"commit": {"message": "Merge release", "author": {"name": "Ana", "date": "2025-01-01T10:00:00Z"}},
+			"parents": [{"sha": "p1"}, {"sha": "p2"}],
+			"stats": {"total": 14, "additions": 12, "deletions": 2},
+			"files": [
+				{"filename": "api/handler.go", "status": "modified", "additions": 12, "deletions": 2},
+				{"filename": "docs/new.md", "previous_filename": "docs/old.md", "status": "renamed"}
+			]
+		}`))
+	})
+
+	commit, err := client.GetCommit(context.Background(), models.Repository{NameWithOwner: "owner/repo"}, "abc1234")
+	if err != nil {
+		t.Fatalf("GetCommit() error: %v", err)
+	}
+	if commit.Author != "Ana" || !slices.Equal(commit.Parents, []string{"p1", "p2"}) {
+		t.Errorf("commit = %+v", commit)
+	}
+	if !commit.HasDetail() || commit.Stats.Additions != 12 || commit.Stats.Deletions != 2 {
+		t.Errorf("stats = %+v", commit.Stats)
+	}
+	if len(commit.Files) != 2 || commit.Files[1].Path() != "docs/old.md → docs/new.md" {
+		t.Errorf("files = %+v", commit.Files)
+	}
+}
+
+func TestRESTClientListRepositories(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{
+			"name": "repo",
+			"full_name": "owner/repo",
+			"description": "A repo",
+			"html_url": "https://github.com/owner/repo",
+			"pushed_at": "2024-06-15T14:30:00Z",
+			"default_branch": "main"
+		}]`))
+	})
+
+	repos, err := client.ListRepositories(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(repos) != 1 {
+		t.Fatalf("got %d repos, want 1", len(repos))
+	}
+	if repos[0].NameWithOwner != "owner/repo" || repos[0].DefaultBranchName != "main" {
+		t.Errorf("repo = %+v", repos[0])
+	}
+}
+
+func TestRESTClientError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Not Found"}`))
+	})
+
+	_, err := client.GetCommits(context.Background(), models.Repository{NameWithOwner: "owner/missing"}, "", models.FilterOptions{PerPage: 50}, 1)
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got := err.Error(); got != "404 Not Found: Not Found" {
+		t.Errorf("error = %q, want %q", got, "404 Not Found: Not Found")
+	}
+}
+
+func TestTokenFromEnv(t *testing.T) {
+	t.Setenv("GH_TOKEN", "from-gh-token")
+	t.Setenv("GITHUB_TOKEN", "from-github-token")
+
+	if got := Token(""); got != "from-gh-token" {
+		t.Errorf("Token() = %q, want %q", got, "from-gh-token")
+	}
+}
+
+func TestTokenFromEnterpriseEnv(t *testing.T) {
+	t.Setenv("GH_TOKEN", "from-gh-token")
+	t.Setenv("GH_ENTERPRISE_TOKEN", "from-enterprise-token")
+
+	if got := Token("ghes.example.com"); got != "from-enterprise-token" {
+		t.Errorf("Token() = %q, want %q", got, "from-enterprise-token")
+	}
+}
+
+func TestBaseURL(t *testing.T) {
+	tests := []struct {
+		hostname string
+		expected string
+	}{
+		{"", DefaultBaseURL},
+		{"github.com", DefaultBaseURL},
+		{"GitHub.com", DefaultBaseURL},
+		{"ghes.example.com", "https://ghes.example.com/api/v3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			if got := BaseURL(tt.hostname); got != tt.expected {
+				t.Errorf("BaseURL(%q) = %q, want %q", tt.hostname, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRESTClientEnterpriseGraphQLEndpoint(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/api/graphql" {
+			w.Write([]byte(`{"data": {"repository": {"refs": {"nodes": [], "pageInfo": {"hasNextPage": false}}}}}`))
+			return
+		}
+		w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewRESTClient(server.URL+"/api/v3", "test-token")
+
+	repo := models.Repository{NameWithOwner: "owner/repo"}
+	if _, err := client.ListBranches(context.Background(), repo); err != nil {
+		t.Fatalf("ListBranches() error: %v", err)
+	}
+	if _, err := client.ListRepositories(context.Background()); err != nil {
+		t.Fatalf("ListRepositories() error: %v", err)
+	}
+	if len(paths) < 2 || paths[0] != "/api/graphql" || !strings.HasPrefix(paths[1], "/api/v3/") {
+		t.Errorf("paths = %v, want GraphQL at /api/graphql and REST under /api/v3", paths)
+	}
+}
+
+func TestRESTClientListRepositoriesMergesSources(t *testing.T) {
+	bodies := map[string]string{
+		"/user/repos":                     `[{"full_name": "me/dotfiles", "pushed_at": "2024-06-01T00:00:00Z"}]`,
+		"/user/orgs":                      `[{"login": "acme"}, {"login": "locked"}]`,
+		"/orgs/acme/repos":                `[{"full_name": "acme/api", "pushed_at": "2024-06-20T00:00:00Z"}, {"full_name": "acme/web", "pushed_at": "2024-05-01T00:00:00Z"}]`,
+		"/user/teams":                     `[{"slug": "platform", "organization": {"login": "acme"}}]`,
+		"/orgs/acme/teams/platform/repos": `[{"full_name": "acme/api", "pushed_at": "2024-06-20T00:00:00Z"}]`,
+		"/user/starred":                   `[{"full_name": "me/dotfiles", "pushed_at": "2024-06-01T00:00:00Z"}]`,
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/orgs/locked/repos" {
+			w.Header().Set("X-GitHub-SSO", "required; url=https://github.com/orgs/locked/sso")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Resource protected by organization SAML enforcement."}`))
+			return
+		}
+		if r.URL.Path == "/user/repos" && r.URL.Query().Get("affiliation") == "collaborator" {
+			w.Write([]byte(`[{"full_name": "friend/tool", "pushed_at": "2024-06-10T00:00:00Z"}]`))
+			return
+		}
+		w.Write([]byte(bodies[r.URL.Path]))
+	})
+
+	repos, err := client.ListRepositories(context.Background())
+	if err != nil {
+		t.Fatalf("ListRepositories() error: %v", err)
+	}
+
+	expected := []struct {
+		name    string
+		sources []models.RepoSource
+	}{
+		{"acme/api", []models.RepoSource{models.SourceOrg, models.SourceTeam}},
+		{"friend/tool", []models.RepoSource{models.SourceCollaborator}},
+		{"me/dotfiles", []models.RepoSource{models.SourceOwner, models.SourceStarred}},
+		{"acme/web", []models.RepoSource{models.SourceOrg}},
+	}
+	if len(repos) != len(expected) {
+		t.Fatalf("repos = %+v", repos)
+	}
+	for i, want := range expected {
+		if repos[i].NameWithOwner != want.name || !slices.Equal(repos[i].Sources, want.sources) {
+			t.Errorf("repos[%d] = %s %v, want %s %v", i, repos[i].NameWithOwner, repos[i].Sources, want.name, want.sources)
+		}
+	}
+}
+
+func TestRESTClientListRepositoriesForOrgs(t *testing.T) {
+	var paths []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		w.Write([]byte(`[]`))
+	}, WithSources(models.SourceOrg), WithOrgs("acme"))
+
+	if _, err := client.ListRepositories(context.Background()); err != nil {
+		t.Fatalf("ListRepositories() error: %v", err)
+	}
+	if !slices.Equal(paths, []string{"/orgs/acme/repos"}) {
+		t.Errorf("paths = %v, want only /orgs/acme/repos", paths)
+	}
+}
+
+func TestRESTClientGetRepository(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/golang/go" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"message": "Not Found"}`))
+			return
+		}
+		w.Write([]byte(`{"name": "go", "full_name": "golang/go", "html_url": "https://github.com/golang/go", "default_branch": "master",
+			"fork": false, "archived": false, "private": false, "visibility": "public", "language": "Go", "stargazers_count": 125000}`))
+	})
+
+	repo, err := client.GetRepository(context.Background(), "golang/go")
+	if err != nil {
+		t.Fatalf("GetRepository() error: %v", err)
+	}
+	if repo.NameWithOwner != "golang/go" || repo.DefaultBranchName != "master" {
+		t.Errorf("repo = %+v", repo)
+	}
+	if repo.PrimaryLanguage != "Go" || repo.Stars != 125000 || repo.Visibility != "public" || repo.IsFork || repo.IsArchived {
+		t.Errorf("repo metadata = %+v", repo)
+	}
+
+	if _, err := client.GetRepository(context.Background(), "golang/missing"); !errors.Is(err, forge.ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRESTClientSearchRepositories(t *testing.T) {
+	var query string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("q")
+		w.Write([]byte(`{"total_count": 2, "items": [{"full_name": "charmbracelet/bubbletea"}, {"full_name": "charmbracelet/bubbles"}]}`))
+	})
+
+	repos, err := client.SearchRepositories(context.Background(), "bubble tea")
+	if err != nil {
+		t.Fatalf("SearchRepositories() error: %v", err)
+	}
+	if query != "bubble tea" || len(repos) != 2 || repos[1].NameWithOwner != "charmbracelet/bubbles" {
+		t.Errorf("query = %q, repos = %+v", query, repos)
+	}
+}
@@ -1,9 +1,11 @@
 package github
 
 import (
+	"context"
 	"fmt"
 	"time"
 
+	"github.com/tkozakas/gh-log/internal/forge"
 	"github.com/tkozakas/gh-log/internal/models"
 )
 
@@ -17,20 +19,86 @@ type commitResponse struct {
 			Date  string `json:"date"`
 		} `json:"author"`
 	} `json:"commit"`
+	Author *struct {
+		Login string `json:"login"`
+	} `json:"author"`
 	HTMLURL string `json:"html_url"`
 }
 
-func GetCommits(owner, repo, branch string, filters models.FilterOptions, page int) ([]models.Commit, bool, error) {
-	endpoint := buildCommitsEndpoint(owner, repo, branch, filters, page)
+type commitDetailResponse struct {
+	commitResponse
+	Parents []struct {
+		SHA string `json:"sha"`
+	} `json:"parents"`
+	Stats struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+	Files []fileResponse `json:"files"`
+}
+
+func (c *apiClient) GetCommits(ctx context.Context, repo models.Repository, branch string, filters models.FilterOptions, page int) (models.CommitPage, error) {
+	owner, name := repo.Owner(), repo.RepoName()
+	endpoint := buildCommitsEndpoint(owner, name, branch, filters, page)
 
 	var response []commitResponse
-	if err := runGHWithJSON(&response, "api", endpoint); err != nil {
-		return nil, false, err
+	resp, err := getJSON(ctx, c.transport, endpoint, &response)
+	if err != nil {
+		return models.CommitPage{}, err
 	}
 
-	commits := mapCommits(response)
-	hasMore := len(commits) == filters.PerPage
-	return commits, hasMore, nil
+	links := forge.ParsePageLinks(resp.header.Get("Link"))
+	result := models.CommitPage{
+		Commits:  mapCommits(response),
+		Page:     page,
+		NextPage: links.Next,
+		LastPage: links.Last,
+	}
+
+	switch {
+	case !result.HasMore():
+		result.LastPage = page
+		result.TotalCount = (page-1)*filters.PerPage + len(result.Commits)
+	case page == 1:
+		result.TotalCount, err = c.countCommits(ctx, owner, name, branch, filters)
+		if err != nil {
+			return models.CommitPage{}, err
+		}
+	}
+	return result, nil
+}
+
+func (c *apiClient) GetCommit(ctx context.Context, repo models.Repository, sha string) (models.Commit, error) {
+	endpoint := fmt.Sprintf("repos/%s/%s/commits/%s", repo.Owner(), repo.RepoName(), sha)
+
+	var response commitDetailResponse
+	if _, err := getJSON(ctx, c.transport, endpoint, &response); err != nil {
+		return models.Commit{}, err
+	}
+
+	commit := mapCommit(response.commitResponse)
+	for _, p := range response.Parents {
+		commit.Parents = append(commit.Parents, p.SHA)
+	}
+	commit.Files = mapFiles(response.Files)
+	commit.Stats = &models.CommitStats{Additions: response.Stats.Additions, Deletions: response.Stats.Deletions}
+	return commit, nil
+}
+
+func (c *apiClient) countCommits(ctx context.Context, owner, repo, branch string, filters models.FilterOptions) (int, error) {
+	filters.PerPage = 1
+	endpoint := buildCommitsEndpoint(owner, repo, branch, filters, 1)
+
+	var response []commitResponse
+	resp, err := getJSON(ctx, c.transport, endpoint, &response)
+	if err != nil {
+		return 0, err
+	}
+
+	if last := forge.ParsePageLinks(resp.header.Get("Link")).Last; last > 0 {
+		return last, nil
+	}
+	return len(response), nil
 }
 
 func buildCommitsEndpoint(owner, repo, branch string, filters models.FilterOptions, page int) string {
@@ -62,7 +130,7 @@ func mapCommits(responses []commitResponse) []models.Commit {
 
 func mapCommit(r commitResponse) models.Commit {
 	date, _ := time.Parse(time.RFC3339, r.Commit.Author.Date)
-	return models.Commit{
+	commit := models.Commit{
 		SHA:     r.SHA,
 		Message: r.Commit.Message,
 		Author:  r.Commit.Author.Name,
@@ -70,4 +138,8 @@ func mapCommit(r commitResponse) models.Commit {
 		Date:    date,
 		URL:     r.HTMLURL,
 	}
+	if r.Author != nil {
+		commit.Login = r.Author.Login
+	}
+	return commit
 }
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const (
+	comparePerPage  = 100
+	maxComparePages = 10
+)
+
+type compareResponse struct {
+	Status   string           `json:"status"`
+	AheadBy  int              `json:"ahead_by"`
+	BehindBy int              `json:"behind_by"`
+	Commits  []commitResponse `json:"commits"`
+	Files    []fileResponse   `json:"files"`
+}
+
+type fileResponse struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+}
+
+func (c *apiClient) Compare(ctx context.Context, repo models.Repository, base, head string) (models.Comparison, error) {
+	endpoint := fmt.Sprintf("repos/%s/%s/compare/%s...%s", repo.Owner(), repo.RepoName(), base, head)
+
+	comparison := models.Comparison{Base: base, Head: head}
+	for page := 1; page > 0 && page <= maxComparePages; {
+		var response compareResponse
+		resp, err := getJSON(ctx, c.transport, fmt.Sprintf("%s?per_page=%d&page=%d", endpoint, comparePerPage, page), &response)
+		if err != nil {
+			return models.Comparison{}, err
+		}
+		if page == 1 {
+			comparison.Status = response.Status
+			comparison.AheadBy = response.AheadBy
+			comparison.BehindBy = response.BehindBy
+			comparison.Files = mapFiles(response.Files)
+		}
+		comparison.Commits = append(comparison.Commits, mapCommits(response.Commits)...)
+		page = forge.ParsePageLinks(resp.header.Get("Link")).Next
+	}
+
+	slices.Reverse(comparison.Commits)
+	return comparison, nil
+}
+
+func mapFiles(responses []fileResponse) []models.FileChange {
+	files := make([]models.FileChange, len(responses))
+	for i, f := range responses {
+		files[i] = models.FileChange{
+			Filename:         f.Filename,
+			PreviousFilename: f.PreviousFilename,
+			Status:           f.Status,
+			Additions:        f.Additions,
+			Deletions:        f.Deletions,
+		}
+	}
+	return files
+}
@@ -0,0 +1,72 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+func TestRESTClientCompare(t *testing.T) {
+	var pages []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/compare/main...release/1.2" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		if page == "1" {
+			w.Header().Set("Link", "<http://"+r.Host+r.URL.Path+`?per_page=100&page=2>; rel="next"`)
+			w.Write([]byte(`{
+				"status": "diverged", "ahead_by": 3, "behind_by": 1,
+				"commits": [
+					{"sha": "aaa", "commit": {"message": "first", "author": {"name": "Ana", "date": "2025-01-01T10:00:00Z"}}},
+					{"sha": "bbb", "commit": {"message": "second", "author": {"name": "Ben", "date": "2025-01-02T10:00:00Z"}}}
+				],
+				"files": [
+					{"filename": "api/handler.go", "status": "modified", "additions": 10, "deletions": 2},
+					{"filename": "docs/new.md", "previous_filename": "docs/old.md", "status": "renamed", "additions": 1, "deletions": 1}
+				]
+			}`))
+			return
+		}
+		w.Write([]byte(`{
+			"status": "diverged", "ahead_by": 3, "behind_by": 1,
+			"commits": [{"sha": "ccc", "commit": {"message": "third", "author": {"name": "Ana", "date": "2025-01-03T10:00:00Z"}}}],
+			"files": [{"filename": "api/handler.go", "status": "modified", "additions": 10, "deletions": 2}]
+		}`))
+	})
+
+	comparison, err := client.Compare(context.Background(), models.Repository{NameWithOwner: "owner/repo"}, "main", "release/1.2")
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+	if len(pages) != 2 {
+		t.Errorf("requested pages %v, want 2", pages)
+	}
+	if comparison.Status != models.CompareDiverged || comparison.AheadBy != 3 || comparison.BehindBy != 1 {
+		t.Errorf("comparison = %+v", comparison)
+	}
+	var shas []string
+	for _, c := range comparison.Commits {
+		shas = append(shas, c.SHA)
+	}
+	if len(shas) != 3 || shas[0] != "ccc" || shas[2] != "aaa" {
+		t.Errorf("commits = %v, want newest first", shas)
+	}
+	if len(comparison.Files) != 2 || !comparison.Files[1].Renamed() || comparison.Additions() != 11 || comparison.Deletions() != 3 {
+		t.Errorf("files = %+v", comparison.Files)
+	}
+}
+
+func TestRESTClientCompareUnknownRef(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message": "Not Found"}`))
+	})
+
+	if _, err := client.Compare(context.Background(), models.Repository{NameWithOwner: "owner/repo"}, "main", "nope"); err == nil {
+		t.Error("Compare() error = nil, want not found")
+	}
+}
@@ -0,0 +1,126 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+)
+
+var (
+	ErrUnauthorized    = forge.ErrUnauthorized
+	ErrForbidden       = forge.ErrForbidden
+	ErrNotFound        = forge.ErrNotFound
+	ErrRateLimited     = forge.ErrRateLimited
+	ErrEmptyRepository = forge.ErrEmptyRepository
+	ErrSSO             = errors.New("SAML SSO authorization required")
+)
+
+type APIError = forge.HTTPError
+
+type RateLimitError struct {
+	APIError
+	Reset      time.Time
+	RetryAfter time.Duration
+	Secondary  bool
+}
+
+type SSOError struct {
+	APIError
+	URL string
+}
+
+func (e *RateLimitError) Error() string {
+	kind := "rate limit exceeded"
+	if e.Secondary {
+		kind = "secondary rate limit exceeded"
+	}
+	if wait := e.Wait(time.Now()); wait > 0 {
+		return fmt.Sprintf("%s, retry in %s", kind, wait.Round(time.Second))
+	}
+	return kind
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == forge.ErrRateLimited || e.APIError.Is(target)
+}
+
+func (e *RateLimitError) Wait(now time.Time) time.Duration {
+	if e.RetryAfter > 0 {
+		return e.RetryAfter
+	}
+	if !e.Reset.IsZero() && e.Reset.After(now) {
+		return e.Reset.Sub(now)
+	}
+	return 0
+}
+
+func (e *SSOError) Error() string {
+	if e.URL == "" {
+		return ErrSSO.Error()
+	}
+	return fmt.Sprintf("%s, authorize your token at %s", ErrSSO, e.URL)
+}
+
+func (e *SSOError) Is(target error) bool {
+	return target == ErrSSO || e.APIError.Is(target)
+}
+
+func newAPIError(resp *response) error {
+	apiErr := APIError{Forge: forge.GitHub, StatusCode: resp.status, Message: forge.ErrorMessage(resp.body)}
+
+	if url, ok := parseSSOHeader(resp.header.Get("X-GitHub-SSO")); ok {
+		return &SSOError{APIError: apiErr, URL: url}
+	}
+	if isRateLimited(resp, apiErr.Message) {
+		return &RateLimitError{
+			APIError:   apiErr,
+			Reset:      parseResetHeader(resp.header.Get("X-RateLimit-Reset")),
+			RetryAfter: parseRetryAfter(resp.header.Get("Retry-After")),
+			Secondary:  strings.Contains(strings.ToLower(apiErr.Message), "secondary rate limit"),
+		}
+	}
+	return &apiErr
+}
+
+func isRateLimited(resp *response, message string) bool {
+	if resp.status != http.StatusForbidden && resp.status != http.StatusTooManyRequests {
+		return false
+	}
+	return resp.status == http.StatusTooManyRequests ||
+		resp.header.Get("X-RateLimit-Remaining") == "0" ||
+		resp.header.Get("Retry-After") != "" ||
+		strings.Contains(strings.ToLower(message), "rate limit")
+}
+
+func parseSSOHeader(header string) (string, bool) {
+	if !strings.HasPrefix(header, "required") {
+		return "", false
+	}
+	for _, part := range strings.Split(header, ";") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(part), "url="); ok {
+			return value, true
+		}
+	}
+	return "", true
+}
+
+func parseResetHeader(header string) time.Time {
+	seconds, err := strconv.ParseInt(header, 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
+
+func parseRetryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
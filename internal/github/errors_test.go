@@ -0,0 +1,147 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		header  http.Header
+		body    string
+		is      error
+		isNot   error
+		message string
+	}{
+		{
+			name:   "notFound",
+			status: http.StatusNotFound,
+			body:   `{"message": "Not Found"}`,
+			is:     forge.ErrNotFound,
+			isNot:  forge.ErrRateLimited,
+		},
+		{
+			name:   "unauthorized",
+			status: http.StatusUnauthorized,
+			body:   `{"message": "Bad credentials"}`,
+			is:     forge.ErrUnauthorized,
+			isNot:  forge.ErrForbidden,
+		},
+		{
+			name:   "forbidden",
+			status: http.StatusForbidden,
+			body:   `{"message": "Resource not accessible by integration"}`,
+			is:     forge.ErrForbidden,
+			isNot:  forge.ErrRateLimited,
+		},
+		{
+			name:   "emptyRepository",
+			status: http.StatusConflict,
+			body:   `{"message": "Git Repository is empty."}`,
+			is:     forge.ErrEmptyRepository,
+			isNot:  forge.ErrNotFound,
+		},
+		{
+			name:   "primaryRateLimit",
+			status: http.StatusForbidden,
+			header: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1718461800"}},
+			body:   `{"message": "API rate limit exceeded"}`,
+			is:     forge.ErrRateLimited,
+			isNot:  forge.ErrNotFound,
+		},
+		{
+			name:   "secondaryRateLimit",
+			status: http.StatusTooManyRequests,
+			header: http.Header{"Retry-After": {"60"}},
+			body:   `{"message": "You have exceeded a secondary rate limit"}`,
+			is:     forge.ErrRateLimited,
+			isNot:  forge.ErrForbidden,
+		},
+		{
+			name:   "sso",
+			status: http.StatusForbidden,
+			header: http.Header{"X-Github-Sso": {"required; url=https://github.com/orgs/acme/sso?authorization_request=abc"}},
+			body:   `{"message": "Resource protected by organization SAML enforcement."}`,
+			is:     ErrSSO,
+			isNot:  forge.ErrRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			err := newAPIError(&response{status: tt.status, header: header, body: []byte(tt.body)})
+			wrapped := fmt.Errorf("loading: %w", err)
+
+			if !errors.Is(wrapped, tt.is) {
+				t.Errorf("errors.Is(%v, %v) = false, want true", err, tt.is)
+			}
+			if errors.Is(wrapped, tt.isNot) {
+				t.Errorf("errors.Is(%v, %v) = true, want false", err, tt.isNot)
+			}
+		})
+	}
+}
+
+func TestErrorsMatchForgeErrors(t *testing.T) {
+	err := fmt.Errorf("loading: %w", newAPIError(&response{status: http.StatusNotFound, header: http.Header{}}))
+
+	if !errors.Is(err, ErrNotFound) || !errors.Is(err, forge.ErrNotFound) {
+		t.Errorf("errors.Is(%v, ErrNotFound) = false, want true for both packages", err)
+	}
+}
+
+func TestRateLimitErrorDetails(t *testing.T) {
+	resp := &response{
+		status: http.StatusForbidden,
+		header: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1718461800"}},
+		body:   []byte(`{"message": "API rate limit exceeded"}`),
+	}
+
+	var rateErr *RateLimitError
+	if !errors.As(newAPIError(resp), &rateErr) {
+		t.Fatal("expected *RateLimitError")
+	}
+	if !rateErr.Reset.Equal(time.Unix(1718461800, 0)) {
+		t.Errorf("Reset = %v, want %v", rateErr.Reset, time.Unix(1718461800, 0))
+	}
+	if got := rateErr.Wait(time.Unix(1718461740, 0)); got != time.Minute {
+		t.Errorf("Wait() = %v, want 1m", got)
+	}
+}
+
+func TestSSOErrorURL(t *testing.T) {
+	resp := &response{
+		status: http.StatusForbidden,
+		header: http.Header{"X-Github-Sso": {"required; url=https://github.com/orgs/acme/sso?authorization_request=abc"}},
+	}
+
+	var ssoErr *SSOError
+	if !errors.As(newAPIError(resp), &ssoErr) {
+		t.Fatal("expected *SSOError")
+	}
+	if ssoErr.URL != "https://github.com/orgs/acme/sso?authorization_request=abc" {
+		t.Errorf("URL = %q", ssoErr.URL)
+	}
+}
+
+func TestGraphQLErrorsIs(t *testing.T) {
+	err := graphQLErrors{{Type: "NOT_FOUND", Message: "Could not resolve to a Repository"}}
+
+	if !errors.Is(err, forge.ErrNotFound) {
+		t.Error("expected NOT_FOUND to match forge.ErrNotFound")
+	}
+	if errors.Is(err, forge.ErrRateLimited) {
+		t.Error("NOT_FOUND should not match forge.ErrRateLimited")
+	}
+}
@@ -0,0 +1,114 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+)
+
+const graphQLEndpoint = "graphql"
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphQLError  `json:"errors"`
+}
+
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Path    []any  `json:"path"`
+}
+
+type graphQLErrors []graphQLError
+
+func (e graphQLErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e graphQLErrors) Is(target error) bool {
+	for _, err := range e {
+		if graphQLErrorTypes[err.Type] == target {
+			return true
+		}
+	}
+	return false
+}
+
+func (e graphQLErrors) forAlias(alias string) graphQLErrors {
+	var matched graphQLErrors
+	for _, err := range e {
+		if len(err.Path) > 0 && err.Path[0] == alias {
+			matched = append(matched, err)
+		}
+	}
+	return matched
+}
+
+func (e graphQLErrors) exceptField(field string) graphQLErrors {
+	var matched graphQLErrors
+	for _, err := range e {
+		if len(err.Path) == 0 || err.Path[len(err.Path)-1] != field {
+			matched = append(matched, err)
+		}
+	}
+	return matched
+}
+
+func (e graphQLErrors) unscoped() graphQLErrors {
+	var matched graphQLErrors
+	for _, err := range e {
+		if len(err.Path) == 0 {
+			matched = append(matched, err)
+		}
+	}
+	return matched
+}
+
+var graphQLErrorTypes = map[string]error{
+	"NOT_FOUND":    forge.ErrNotFound,
+	"FORBIDDEN":    forge.ErrForbidden,
+	"RATE_LIMITED": forge.ErrRateLimited,
+}
+
+type pageInfo struct {
+	HasNextPage bool   `json:"hasNextPage"`
+	EndCursor   string `json:"endCursor"`
+}
+
+func queryGraphQL(ctx context.Context, t transport, query string, variables map[string]any, result any) error {
+	body, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+
+	resp, err := send(ctx, t, request{method: http.MethodPost, endpoint: graphQLEndpoint, body: body})
+	if err != nil {
+		return err
+	}
+
+	var response graphQLResponse
+	if err := json.Unmarshal(resp.body, &response); err != nil {
+		return err
+	}
+	if len(response.Data) > 0 {
+		if err := json.Unmarshal(response.Data, result); err != nil {
+			return err
+		}
+	}
+	if len(response.Errors) > 0 {
+		return graphQLErrors(response.Errors)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const historyBatchSize = 10
+
+const historyFragment = `fragment historyFields on CommitHistoryConnection {
+  totalCount
+  pageInfo { hasNextPage endCursor }
+  nodes {
+    oid
+    message
+    url
+    author { name email date user { login } }
+  }
+}`
+
+const authorIDQuery = `query($login: String!) { user(login: $login) { id } }`
+
+type historyResponse struct {
+	IsEmpty bool `json:"isEmpty"`
+	Object  *struct {
+		History struct {
+			TotalCount int           `json:"totalCount"`
+			PageInfo   pageInfo      `json:"pageInfo"`
+			Nodes      []historyNode `json:"nodes"`
+		} `json:"history"`
+	} `json:"object"`
+}
+
+type historyNode struct {
+	OID     string `json:"oid"`
+	Message string `json:"message"`
+	URL     string `json:"url"`
+	Author  struct {
+		Name  string `json:"name"`
+		Email string `json:"email"`
+		Date  string `json:"date"`
+		User  *struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"author"`
+}
+
+type authorIDResponse struct {
+	User *struct {
+		ID string `json:"id"`
+	} `json:"user"`
+}
+
+func (c *apiClient) GetHistories(ctx context.Context, refs []forge.HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error) {
+	author, err := c.resolveAuthor(ctx, filters.Author)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]models.RepoCommits, 0, len(refs))
+	for start := 0; start < len(refs); start += historyBatchSize {
+		end := min(start+historyBatchSize, len(refs))
+		batch, err := c.fetchHistoryBatch(ctx, refs[start:end], filters, author)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, batch...)
+	}
+	return results, nil
+}
+
+func (c *apiClient) fetchHistoryBatch(ctx context.Context, refs []forge.HistoryRef, filters models.FilterOptions, author map[string]any) ([]models.RepoCommits, error) {
+	query, variables := buildHistoryQuery(refs, filters, author)
+
+	response := make(map[string]historyResponse, len(refs))
+	err := queryGraphQL(ctx, c.transport, query, variables, &response)
+
+	var gqlErrs graphQLErrors
+	if err != nil && (!errors.As(err, &gqlErrs) || len(gqlErrs.unscoped()) > 0) {
+		return nil, err
+	}
+
+	results := make([]models.RepoCommits, len(refs))
+	for i, ref := range refs {
+		alias := historyAlias(i)
+		if errs := gqlErrs.forAlias(alias); len(errs) > 0 {
+			results[i] = forge.FailedHistory(ref, errs)
+			continue
+		}
+		results[i] = mapHistory(ref, response[alias])
+	}
+	return results, nil
+}
+
+func (c *apiClient) resolveAuthor(ctx context.Context, author string) (map[string]any, error) {
+	if author == "" {
+		return nil, nil
+	}
+	if strings.Contains(author, "@") {
+		return map[string]any{"emails": []string{author}}, nil
+	}
+
+	var response authorIDResponse
+	if err := queryGraphQL(ctx, c.transport, authorIDQuery, map[string]any{"login": author}, &response); err != nil {
+		return nil, err
+	}
+	if response.User == nil {
+		return nil, fmt.Errorf("unknown author %q", author)
+	}
+	return map[string]any{"id": response.User.ID}, nil
+}
+
+func buildHistoryQuery(refs []forge.HistoryRef, filters models.FilterOptions, author map[string]any) (string, map[string]any) {
+	variables := map[string]any{"first": filters.PerPage}
+	params := []string{"$first: Int!", "$since: GitTimestamp", "$until: GitTimestamp", "$author: CommitAuthor"}
+
+	if filters.DateFrom != "" {
+		variables["since"] = filters.DateFrom + "T00:00:00Z"
+	}
+	if filters.DateTo != "" {
+		variables["until"] = filters.DateTo + "T23:59:59Z"
+	}
+	if author != nil {
+		variables["author"] = author
+	}
+
+	var fields strings.Builder
+	for i, ref := range refs {
+		alias := historyAlias(i)
+		params = append(params,
+			fmt.Sprintf("$owner%d: String!", i),
+			fmt.Sprintf("$name%d: String!", i),
+			fmt.Sprintf("$expr%d: String!", i),
+			fmt.Sprintf("$after%d: String", i))
+
+		variables[fmt.Sprintf("owner%d", i)] = ref.Repository.Owner()
+		variables[fmt.Sprintf("name%d", i)] = ref.Repository.RepoName()
+		variables[fmt.Sprintf("expr%d", i)] = historyExpression(ref.Branch)
+		if ref.Cursor != "" {
+			variables[fmt.Sprintf("after%d", i)] = ref.Cursor
+		}
+
+		fmt.Fprintf(&fields, "  %s: repository(owner: $owner%d, name: $name%d) {\n", alias, i, i)
+		fields.WriteString("    isEmpty\n")
+		fmt.Fprintf(&fields, "    object(expression: $expr%d) { ... on Commit { history(first: $first, after: $after%d, since: $since, until: $until, author: $author) { ...historyFields } } }\n", i, i)
+		fields.WriteString("  }\n")
+	}
+
+	query := fmt.Sprintf("query(%s) {\n%s}\n%s", strings.Join(params, ", "), fields.String(), historyFragment)
+	return query, variables
+}
+
+func historyAlias(index int) string {
+	return fmt.Sprintf("r%d", index)
+}
+
+func historyExpression(branch string) string {
+	if branch == "" {
+		return "HEAD"
+	}
+	return "refs/heads/" + branch
+}
+
+func mapHistory(ref forge.HistoryRef, r historyResponse) models.RepoCommits {
+	rc := models.RepoCommits{
+		Repository: ref.Repository,
+		Branch:     ref.Branch,
+	}
+	if r.IsEmpty {
+		return forge.FailedHistory(ref, forge.ErrEmptyRepository)
+	}
+	if r.Object == nil {
+		return forge.FailedHistory(ref, fmt.Errorf("branch %q: %w", ref.Branch, forge.ErrNotFound))
+	}
+
+	history := r.Object.History
+	rc.Commits = mapHistoryNodes(history.Nodes)
+	rc.HasMore = history.PageInfo.HasNextPage
+	rc.Cursor = history.PageInfo.EndCursor
+	rc.TotalCount = history.TotalCount
+	return rc
+}
+
+func mapHistoryNodes(nodes []historyNode) []models.Commit {
+	commits := make([]models.Commit, len(nodes))
+	for i, n := range nodes {
+		date, _ := time.Parse(time.RFC3339, n.Author.Date)
+		commits[i] = models.Commit{
+			SHA:     n.OID,
+			Message: n.Message,
+			Author:  n.Author.Name,
+			Email:   n.Author.Email,
+			Date:    date,
+			URL:     n.URL,
+		}
+		if n.Author.User != nil {
+			commits[i].Login = n.Author.User.Login
+		}
+	}
+	return commits
+}
@@ -0,0 +1,163 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+func TestHistoryExpression(t *testing.T) {
+	tests := []struct {
+		name     string
+		branch   string
+		expected string
+	}{
+		{"defaultBranch", "", "HEAD"},
+		{"namedBranch", "main", "refs/heads/main"},
+		{"nestedBranch", "release/1.0", "refs/heads/release/1.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := historyExpression(tt.branch); got != tt.expected {
+				t.Errorf("historyExpression() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildHistoryQuery(t *testing.T) {
+	refs := []forge.HistoryRef{
+		{Repository: models.Repository{NameWithOwner: "owner/one"}, Branch: "main"},
+		{Repository: models.Repository{NameWithOwner: "owner/two"}, Branch: "dev", Cursor: "abc"},
+	}
+	filters := models.FilterOptions{PerPage: 50, DateFrom: "2024-01-01", DateTo: "2024-06-30"}
+
+	query, variables := buildHistoryQuery(refs, filters, map[string]any{"emails": []string{"a@b.c"}})
+
+	for _, alias := range []string{"r0: repository", "r1: repository"} {
+		if !strings.Contains(query, alias) {
+			t.Errorf("query missing %q", alias)
+		}
+	}
+
+	expected := map[string]any{
+		"first":  50,
+		"since":  "2024-01-01T00:00:00Z",
+		"until":  "2024-06-30T23:59:59Z",
+		"owner0": "owner",
+		"name0":  "one",
+		"expr0":  "refs/heads/main",
+		"owner1": "owner",
+		"name1":  "two",
+		"expr1":  "refs/heads/dev",
+		"after1": "abc",
+	}
+	for k, v := range expected {
+		if variables[k] != v {
+			t.Errorf("variables[%q] = %v, want %v", k, variables[k], v)
+		}
+	}
+	if _, ok := variables["after0"]; ok {
+		t.Error("after0 should be unset without a cursor")
+	}
+	if variables["author"] == nil {
+		t.Error("author should be set")
+	}
+}
+
+func TestRESTClientGetHistories(t *testing.T) {
+	var requests int
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var body graphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+		if strings.Contains(body.Query, "user(login") {
+			w.Write([]byte(`{"data": {"user": {"id": "U_1"}}}`))
+			return
+		}
+		if got := body.Variables["author"]; got == nil {
+			t.Error("expected author variable")
+		}
+		w.Write([]byte(`{"data": {
+			"r0": {"object": {"history": {
+				"totalCount": 120,
+				"pageInfo": {"hasNextPage": true, "endCursor": "c1"},
+				"nodes": [{"oid": "abc1234567", "message": "Fix bug", "url": "https://github.com/owner/one/commit/abc1234567", "author": {"name": "John", "email": "john@example.com", "date": "2024-06-15T14:30:00Z", "user": {"login": "john"}}}]
+			}}},
+			"r1": {"object": null}
+		}}`))
+	})
+
+	refs := []forge.HistoryRef{
+		{Repository: models.Repository{NameWithOwner: "owner/one"}, Branch: "main"},
+		{Repository: models.Repository{NameWithOwner: "owner/two"}, Branch: "gone"},
+	}
+	results, err := client.GetHistories(context.Background(), refs, models.FilterOptions{PerPage: 50, Author: "john"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("requests = %d, want 2 (author lookup + batch)", requests)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+
+	one := results[0]
+	if len(one.Commits) != 1 || one.Commits[0].Author != "John" || one.Commits[0].Login != "john" {
+		t.Errorf("commits = %+v", one.Commits)
+	}
+	if !one.HasMore || one.Cursor != "c1" || one.TotalCount != 120 {
+		t.Errorf("pagination = hasMore:%v cursor:%q total:%d", one.HasMore, one.Cursor, one.TotalCount)
+	}
+	if results[1].Repository.NameWithOwner != "owner/two" || !errors.Is(results[1].Err, forge.ErrNotFound) {
+		t.Errorf("results[1] = %+v, want failed with forge.ErrNotFound", results[1])
+	}
+}
+
+func TestRESTClientGetHistoriesPartialFailure(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"data": {
+				"r0": {"object": {"history": {"totalCount": 1, "pageInfo": {"hasNextPage": false}, "nodes": [{"oid": "abc"}]}}},
+				"r1": null
+			},
+			"errors": [{"type": "NOT_FOUND", "path": ["r1"], "message": "Could not resolve to a Repository with the name 'owner/gone'."}]
+		}`))
+	})
+
+	refs := []forge.HistoryRef{
+		{Repository: models.Repository{NameWithOwner: "owner/one"}},
+		{Repository: models.Repository{NameWithOwner: "owner/gone"}},
+	}
+	results, err := client.GetHistories(context.Background(), refs, models.FilterOptions{PerPage: 50})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if results[0].Failed() || len(results[0].Commits) != 1 {
+		t.Errorf("results[0] = %+v, want one commit", results[0])
+	}
+	if !results[1].Failed() || !errors.Is(results[1].Err, forge.ErrNotFound) {
+		t.Errorf("results[1] = %+v, want failed with forge.ErrNotFound", results[1])
+	}
+}
+
+func TestRESTClientGetHistoriesUnscopedError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"data": null, "errors": [{"message": "Bad credentials"}]}`))
+	})
+
+	refs := []forge.HistoryRef{{Repository: models.Repository{NameWithOwner: "owner/one"}}}
+	if _, err := client.GetHistories(context.Background(), refs, models.FilterOptions{PerPage: 50}); err == nil {
+		t.Error("expected error for an unscoped GraphQL error")
+	}
+}
@@ -0,0 +1,177 @@
+package github
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const (
+	maxRetries          = 3
+	maxRateLimitWait    = 2 * time.Minute
+	secondaryLimitWait  = time.Minute
+	serverErrorBackoff  = 500 * time.Millisecond
+	defaultRateResource = "core"
+)
+
+type rateLimitTransport struct {
+	next   transport
+	sleep  func(context.Context, time.Duration) error
+	now    func() time.Time
+	mu     sync.Mutex
+	limits map[string]models.RateLimit
+}
+
+func newRateLimitTransport(next transport) *rateLimitTransport {
+	return &rateLimitTransport{
+		next:   next,
+		sleep:  sleepContext,
+		now:    time.Now,
+		limits: make(map[string]models.RateLimit),
+	}
+}
+
+func (t *rateLimitTransport) do(ctx context.Context, req request) (*response, error) {
+	for attempt := 0; ; attempt++ {
+		if wait := t.exhaustedWait(resourceFor(req)); wait > 0 && wait <= maxRateLimitWait {
+			if err := t.sleep(ctx, wait); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.next.do(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		t.record(resp.header)
+
+		wait, retry := t.retryDelay(resp, attempt)
+		if !retry || attempt >= maxRetries {
+			return resp, nil
+		}
+		if err := t.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *rateLimitTransport) RateLimit() models.RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var lowest models.RateLimit
+	for _, limit := range t.limits {
+		if !lowest.Known() || limit.Remaining*lowest.Limit < lowest.Remaining*limit.Limit {
+			lowest = limit
+		}
+	}
+	return lowest
+}
+
+func (t *rateLimitTransport) record(header http.Header) {
+	limit, ok := parseRateLimit(header)
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.limits[limit.Resource] = limit
+}
+
+func (t *rateLimitTransport) exhaustedWait(resource string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	limit, ok := t.limits[resource]
+	if !ok || limit.Remaining > 0 {
+		return 0
+	}
+	return limit.Reset.Sub(t.now())
+}
+
+func (t *rateLimitTransport) retryDelay(resp *response, attempt int) (time.Duration, bool) {
+	switch {
+	case resp.status >= http.StatusInternalServerError:
+		return backoff(attempt), true
+	case resp.status == http.StatusForbidden || resp.status == http.StatusTooManyRequests:
+		return t.rateLimitDelay(resp)
+	default:
+		return 0, false
+	}
+}
+
+func (t *rateLimitTransport) rateLimitDelay(resp *response) (time.Duration, bool) {
+	err := newAPIError(resp)
+	rateErr, ok := err.(*RateLimitError)
+	if !ok {
+		return 0, false
+	}
+
+	wait := rateErr.Wait(t.now())
+	if wait == 0 && rateErr.Secondary {
+		wait = secondaryLimitWait
+	}
+	if wait <= 0 || wait > maxRateLimitWait {
+		return 0, false
+	}
+	return wait + jitter(time.Second), true
+}
+
+func parseRateLimit(header http.Header) (models.RateLimit, bool) {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return models.RateLimit{}, false
+	}
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return models.RateLimit{}, false
+	}
+
+	resource := header.Get("X-RateLimit-Resource")
+	if resource == "" {
+		resource = defaultRateResource
+	}
+	return models.RateLimit{
+		Resource:  resource,
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     parseResetHeader(header.Get("X-RateLimit-Reset")),
+	}, true
+}
+
+func resourceFor(req request) string {
+	if req.endpoint == graphQLEndpoint {
+		return "graphql"
+	}
+	return defaultRateResource
+}
+
+func backoff(attempt int) time.Duration {
+	base := serverErrorBackoff << attempt
+	return base + jitter(base)
+}
+
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return rand.N(max)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
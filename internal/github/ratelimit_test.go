@@ -0,0 +1,131 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+type fakeTransport struct {
+	responses []*response
+	requests  int
+}
+
+func (f *fakeTransport) do(ctx context.Context, req request) (*response, error) {
+	resp := f.responses[min(f.requests, len(f.responses)-1)]
+	f.requests++
+	return resp, nil
+}
+
+func newTestRateLimitTransport(next transport, now time.Time) (*rateLimitTransport, *[]time.Duration) {
+	var sleeps []time.Duration
+	t := newRateLimitTransport(next)
+	t.now = func() time.Time { return now }
+	t.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return nil
+	}
+	return t, &sleeps
+}
+
+func TestRateLimitTransportRetriesServerErrors(t *testing.T) {
+	next := &fakeTransport{responses: []*response{
+		{status: http.StatusBadGateway, header: http.Header{}},
+		{status: http.StatusServiceUnavailable, header: http.Header{}},
+		{status: http.StatusOK, header: http.Header{}},
+	}}
+	rt, sleeps := newTestRateLimitTransport(next, time.Now())
+
+	resp, err := rt.do(context.Background(), request{method: http.MethodGet, endpoint: "user/repos"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.status != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.status)
+	}
+	if len(*sleeps) != 2 {
+		t.Fatalf("sleeps = %v, want 2 backoffs", *sleeps)
+	}
+	if (*sleeps)[0] < serverErrorBackoff || (*sleeps)[1] < 2*serverErrorBackoff {
+		t.Errorf("sleeps = %v, want exponential backoff", *sleeps)
+	}
+}
+
+func TestRateLimitTransportGivesUpAfterMaxRetries(t *testing.T) {
+	next := &fakeTransport{responses: []*response{{status: http.StatusInternalServerError, header: http.Header{}}}}
+	rt, _ := newTestRateLimitTransport(next, time.Now())
+
+	resp, err := rt.do(context.Background(), request{method: http.MethodGet, endpoint: "user/repos"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.status != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", resp.status)
+	}
+	if next.requests != maxRetries+1 {
+		t.Errorf("requests = %d, want %d", next.requests, maxRetries+1)
+	}
+}
+
+func TestRateLimitTransportWaitsForSecondaryLimit(t *testing.T) {
+	next := &fakeTransport{responses: []*response{
+		{status: http.StatusTooManyRequests, header: http.Header{"Retry-After": {"30"}}, body: []byte(`{"message": "You have exceeded a secondary rate limit"}`)},
+		{status: http.StatusOK, header: http.Header{}},
+	}}
+	rt, sleeps := newTestRateLimitTransport(next, time.Now())
+
+	resp, err := rt.do(context.Background(), request{method: http.MethodPost, endpoint: graphQLEndpoint})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.status != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.status)
+	}
+	if len(*sleeps) != 1 || (*sleeps)[0] < 30*time.Second {
+		t.Errorf("sleeps = %v, want one wait of at least 30s", *sleeps)
+	}
+}
+
+func TestRateLimitTransportDoesNotWaitForDistantReset(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	next := &fakeTransport{responses: []*response{{
+		status: http.StatusForbidden,
+		header: http.Header{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1700003600"}},
+		body:   []byte(`{"message": "API rate limit exceeded"}`),
+	}}}
+	rt, sleeps := newTestRateLimitTransport(next, now)
+
+	resp, _ := rt.do(context.Background(), request{method: http.MethodGet, endpoint: "user/repos"})
+	if resp.status != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", resp.status)
+	}
+	if len(*sleeps) != 0 {
+		t.Errorf("sleeps = %v, want none for an hour-long reset", *sleeps)
+	}
+}
+
+func TestRateLimitTransportTracksLowestQuota(t *testing.T) {
+	next := &fakeTransport{responses: []*response{
+		{status: http.StatusOK, header: http.Header{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"4000"}, "X-Ratelimit-Resource": {"core"}}},
+		{status: http.StatusOK, header: http.Header{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"250"}, "X-Ratelimit-Resource": {"graphql"}}},
+	}}
+	rt, _ := newTestRateLimitTransport(next, time.Now())
+
+	rt.do(context.Background(), request{method: http.MethodGet, endpoint: "user/repos"})
+	rt.do(context.Background(), request{method: http.MethodPost, endpoint: graphQLEndpoint})
+
+	limit := rt.RateLimit()
+	if limit.Resource != "graphql" || limit.Remaining != 250 || limit.Limit != 5000 {
+		t.Errorf("RateLimit() = %+v, want graphql 250/5000", limit)
+	}
+}
+
+func TestSleepContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := sleepContext(ctx, time.Hour); err != context.Canceled {
+		t.Errorf("sleepContext() = %v, want context.Canceled", err)
+	}
+}
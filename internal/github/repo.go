@@ -1,46 +1,195 @@
 package github
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/tkozakas/gh-log/internal/forge"
 	"github.com/tkozakas/gh-log/internal/models"
 )
 
+const (
+	reposPerPage   = 100
+	maxRepoPages   = 10
+	searchPageSize = 20
+)
+
 type repoResponse struct {
-	Name             string `json:"name"`
-	NameWithOwner    string `json:"nameWithOwner"`
-	Description      string `json:"description"`
-	URL              string `json:"url"`
-	PushedAt         string `json:"pushedAt"`
-	DefaultBranchRef struct {
-		Name string `json:"name"`
-	} `json:"defaultBranchRef"`
+	Name            string `json:"name"`
+	FullName        string `json:"full_name"`
+	Description     string `json:"description"`
+	HTMLURL         string `json:"html_url"`
+	PushedAt        string `json:"pushed_at"`
+	DefaultBranch   string `json:"default_branch"`
+	Fork            bool   `json:"fork"`
+	Archived        bool   `json:"archived"`
+	Private         bool   `json:"private"`
+	Visibility      string `json:"visibility"`
+	Language        string `json:"language"`
+	StargazersCount int    `json:"stargazers_count"`
+}
+
+type orgResponse struct {
+	Login string `json:"login"`
+}
+
+type teamResponse struct {
+	Slug         string      `json:"slug"`
+	Organization orgResponse `json:"organization"`
+}
+
+func (c *apiClient) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	var repos []models.Repository
+	index := make(map[string]int)
+	for _, source := range c.sources {
+		found, err := c.listSource(ctx, source)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s repositories: %w", source, err)
+		}
+		for _, r := range found {
+			i, ok := index[r.NameWithOwner]
+			if !ok {
+				index[r.NameWithOwner] = len(repos)
+				repos = append(repos, r)
+				continue
+			}
+			if !repos[i].HasSource(source) {
+				repos[i].Sources = append(repos[i].Sources, source)
+			}
+		}
+	}
+
+	slices.SortStableFunc(repos, func(a, b models.Repository) int {
+		return b.PushedAt.Compare(a.PushedAt)
+	})
+	return repos, nil
+}
+
+func (c *apiClient) GetRepository(ctx context.Context, nameWithOwner string) (models.Repository, error) {
+	var response repoResponse
+	if _, err := getJSON(ctx, c.transport, "repos/"+nameWithOwner, &response); err != nil {
+		return models.Repository{}, fmt.Errorf("%s: %w", nameWithOwner, err)
+	}
+	return mapRepository(response), nil
+}
+
+func (c *apiClient) SearchRepositories(ctx context.Context, query string) ([]models.Repository, error) {
+	var response struct {
+		Items []repoResponse `json:"items"`
+	}
+	endpoint := fmt.Sprintf("search/repositories?q=%s&per_page=%d", url.QueryEscape(query), searchPageSize)
+	if _, err := getJSON(ctx, c.transport, endpoint, &response); err != nil {
+		return nil, err
+	}
+	return mapRepositories(response.Items), nil
 }
 
-type branchResponse struct {
-	Name string `json:"name"`
+func (c *apiClient) listSource(ctx context.Context, source models.RepoSource) ([]models.Repository, error) {
+	switch source {
+	case models.SourceOwner:
+		return c.listRepos(ctx, "user/repos?affiliation=owner&sort=pushed", source)
+	case models.SourceCollaborator:
+		return c.listRepos(ctx, "user/repos?affiliation=collaborator&sort=pushed", source)
+	case models.SourceStarred:
+		return c.listRepos(ctx, "user/starred?sort=updated", source)
+	case models.SourceOrg:
+		return c.listOrgRepos(ctx)
+	case models.SourceTeam:
+		return c.listTeamRepos(ctx)
+	default:
+		return nil, fmt.Errorf("unknown repository source %q", source)
+	}
 }
 
-func ListRepositories() ([]models.Repository, error) {
-	var response []repoResponse
-	err := runGHWithJSON(&response,
-		"repo", "list",
-		"--json", "name,nameWithOwner,description,url,pushedAt,defaultBranchRef",
-		"--limit", "1000",
-	)
+func (c *apiClient) listOrgRepos(ctx context.Context) ([]models.Repository, error) {
+	orgs := c.orgs
+	if len(orgs) == 0 {
+		response, err := getPages[orgResponse](ctx, c.transport, "user/orgs")
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range response {
+			orgs = append(orgs, o.Login)
+		}
+	}
+
+	var repos []models.Repository
+	for _, org := range orgs {
+		endpoint := fmt.Sprintf("orgs/%s/repos?type=all&sort=pushed", url.PathEscape(org))
+		found, err := c.listRepos(ctx, endpoint, models.SourceOrg)
+		if errors.Is(err, ErrSSO) && len(c.orgs) == 0 {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", org, err)
+		}
+		repos = append(repos, found...)
+	}
+	return repos, nil
+}
+
+func (c *apiClient) listTeamRepos(ctx context.Context) ([]models.Repository, error) {
+	teams, err := getPages[teamResponse](ctx, c.transport, "user/teams")
 	if err != nil {
 		return nil, err
 	}
-	return mapRepositories(response), nil
+
+	var repos []models.Repository
+	for _, team := range teams {
+		org := team.Organization.Login
+		if len(c.orgs) > 0 && !slices.ContainsFunc(c.orgs, func(o string) bool { return strings.EqualFold(o, org) }) {
+			continue
+		}
+		endpoint := fmt.Sprintf("orgs/%s/teams/%s/repos", url.PathEscape(org), url.PathEscape(team.Slug))
+		found, err := c.listRepos(ctx, endpoint, models.SourceTeam)
+		if errors.Is(err, ErrSSO) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", org, team.Slug, err)
+		}
+		repos = append(repos, found...)
+	}
+	return repos, nil
 }
 
-func ListBranches(owner, repo string) ([]string, error) {
-	var response []branchResponse
-	err := runGHWithJSON(&response, "api", "repos/"+owner+"/"+repo+"/branches")
+func (c *apiClient) listRepos(ctx context.Context, endpoint string, source models.RepoSource) ([]models.Repository, error) {
+	response, err := getPages[repoResponse](ctx, c.transport, endpoint)
 	if err != nil {
 		return nil, err
 	}
-	return extractBranchNames(response), nil
+	repos := mapRepositories(response)
+	for i := range repos {
+		repos[i].Sources = []models.RepoSource{source}
+	}
+	return repos, nil
+}
+
+func getPages[T any](ctx context.Context, t transport, endpoint string) ([]T, error) {
+	var items []T
+	for page := 1; page > 0 && page <= maxRepoPages; {
+		var response []T
+		resp, err := getJSON(ctx, t, pageEndpoint(endpoint, page), &response)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, response...)
+		page = forge.ParsePageLinks(resp.header.Get("Link")).Next
+	}
+	return items, nil
+}
+
+func pageEndpoint(endpoint string, page int) string {
+	sep := "?"
+	if strings.Contains(endpoint, "?") {
+		sep = "&"
+	}
+	return fmt.Sprintf("%s%sper_page=%d&page=%d", endpoint, sep, reposPerPage, page)
 }
 
 func mapRepositories(responses []repoResponse) []models.Repository {
@@ -55,18 +204,16 @@ func mapRepository(r repoResponse) models.Repository {
 	pushedAt, _ := time.Parse(time.RFC3339, r.PushedAt)
 	return models.Repository{
 		Name:              r.Name,
-		NameWithOwner:     r.NameWithOwner,
+		NameWithOwner:     r.FullName,
 		Description:       r.Description,
-		URL:               r.URL,
+		URL:               r.HTMLURL,
 		PushedAt:          pushedAt,
-		DefaultBranchName: r.DefaultBranchRef.Name,
-	}
-}
-
-func extractBranchNames(responses []branchResponse) []string {
-	names := make([]string, len(responses))
-	for i, b := range responses {
-		names[i] = b.Name
+		DefaultBranchName: r.DefaultBranch,
+		IsFork:            r.Fork,
+		IsArchived:        r.Archived,
+		IsPrivate:         r.Private,
+		Visibility:        r.Visibility,
+		PrimaryLanguage:   r.Language,
+		Stars:             r.StargazersCount,
 	}
-	return names
 }
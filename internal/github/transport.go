@@ -0,0 +1,188 @@
+package github
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second
+
+type transport interface {
+	do(ctx context.Context, req request) (*response, error)
+}
+
+type request struct {
+	method   string
+	endpoint string
+	header   http.Header
+	body     []byte
+}
+
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+type httpTransport struct {
+	baseURL    string
+	graphQLURL string
+	token      string
+	client     *http.Client
+}
+
+func newHTTPTransport(baseURL, token string) *httpTransport {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	graphQLURL := baseURL + "/" + graphQLEndpoint
+	if api, ok := strings.CutSuffix(baseURL, "/v3"); ok {
+		graphQLURL = api + "/" + graphQLEndpoint
+	}
+	return &httpTransport{
+		baseURL:    baseURL,
+		graphQLURL: graphQLURL,
+		token:      token,
+		client:     &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (t *httpTransport) url(endpoint string) string {
+	if endpoint == graphQLEndpoint {
+		return t.graphQLURL
+	}
+	return t.baseURL + "/" + endpoint
+}
+
+func (t *httpTransport) do(ctx context.Context, r request) (*response, error) {
+	req, err := http.NewRequestWithContext(ctx, r.method, t.url(r.endpoint), bytes.NewReader(r.body))
+	if err != nil {
+		return nil, err
+	}
+	if r.body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, values := range r.header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if t.token != "" {
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: resp.StatusCode, header: resp.Header, body: body}, nil
+}
+
+type ghTransport struct {
+	hostname string
+}
+
+func (t ghTransport) do(ctx context.Context, r request) (*response, error) {
+	args := hostArgs(t.hostname, "api", "--include", "--method", r.method, r.endpoint)
+	for name, values := range r.header {
+		for _, v := range values {
+			args = append(args, "--header", name+": "+v)
+		}
+	}
+	if r.body != nil {
+		args = append(args, "--input", "-")
+	}
+
+	output, err := runGH(ctx, r.body, args...)
+	if err != nil {
+		if resp, parseErr := parseIncludeOutput(output); parseErr == nil && resp.status > 0 {
+			return resp, nil
+		}
+		return nil, err
+	}
+	return parseIncludeOutput(output)
+}
+
+func runGH(ctx context.Context, stdin []byte, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "gh", args...)
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return stdout.Bytes(), errors.New(strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func parseIncludeOutput(output []byte) (*response, error) {
+	reader := textproto.NewReader(bufio.NewReader(bytes.NewReader(output)))
+	statusLine, err := reader.ReadLine()
+	if err != nil {
+		return nil, fmt.Errorf("reading status line: %w", err)
+	}
+	status, err := parseStatusLine(statusLine)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := reader.ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("reading headers: %w", err)
+	}
+
+	body, err := io.ReadAll(reader.R)
+	if err != nil {
+		return nil, err
+	}
+	return &response{status: status, header: http.Header(header), body: body}, nil
+}
+
+func parseStatusLine(line string) (int, error) {
+	_, rest, ok := strings.Cut(line, " ")
+	if !ok || !strings.HasPrefix(line, "HTTP/") {
+		return 0, fmt.Errorf("malformed status line %q", line)
+	}
+	code, _, _ := strings.Cut(rest, " ")
+	return strconv.Atoi(code)
+}
+
+func send(ctx context.Context, t transport, req request) (*response, error) {
+	resp, err := t.do(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.status >= http.StatusMultipleChoices {
+		return nil, newAPIError(resp)
+	}
+	return resp, nil
+}
+
+func getJSON(ctx context.Context, t transport, endpoint string, result any) (*response, error) {
+	resp, err := send(ctx, t, request{method: http.MethodGet, endpoint: endpoint})
+	if err != nil {
+		return nil, err
+	}
+	return resp, json.Unmarshal(resp.body, result)
+}
@@ -0,0 +1,51 @@
+package github
+
+import (
+	"testing"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+)
+
+func TestParseIncludeOutput(t *testing.T) {
+	output := "HTTP/2.0 200 OK\nContent-Type: application/json\nLink: <https://api.github.com/x?page=2>; rel=\"next\"\n\n[{\"name\":\"main\"}]"
+
+	resp, err := parseIncludeOutput([]byte(output))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.status != 200 {
+		t.Errorf("status = %d, want 200", resp.status)
+	}
+	if got := forge.ParsePageLinks(resp.header.Get("Link")).Next; got != 2 {
+		t.Errorf("next page = %d, want 2", got)
+	}
+	if got := string(resp.body); got != `[{"name":"main"}]` {
+		t.Errorf("body = %q", got)
+	}
+}
+
+func TestParseStatusLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected int
+		wantErr  bool
+	}{
+		{"http2", "HTTP/2.0 404 Not Found", 404, false},
+		{"http11", "HTTP/1.1 200 OK", 200, false},
+		{"noReason", "HTTP/2.0 304", 304, false},
+		{"garbage", "not a status line", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStatusLine(tt.line)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStatusLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("parseStatusLine() = %d, want %d", got, tt.expected)
+			}
+		})
+	}
+}
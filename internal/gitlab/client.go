@@ -0,0 +1,355 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const (
+	perPage        = 100
+	maxPages       = 10
+	searchPageSize = 20
+)
+
+type Client struct {
+	rest *forge.RESTClient
+}
+
+type projectResponse struct {
+	Path              string          `json:"path"`
+	PathWithNamespace string          `json:"path_with_namespace"`
+	Description       string          `json:"description"`
+	WebURL            string          `json:"web_url"`
+	LastActivityAt    string          `json:"last_activity_at"`
+	DefaultBranch     string          `json:"default_branch"`
+	Archived          bool            `json:"archived"`
+	Visibility        string          `json:"visibility"`
+	StarCount         int             `json:"star_count"`
+	ForkedFrom        json.RawMessage `json:"forked_from_project"`
+}
+
+type branchResponse struct {
+	Name      string `json:"name"`
+	Protected bool   `json:"protected"`
+	Commit    struct {
+		ID            string `json:"id"`
+		CommittedDate string `json:"committed_date"`
+	} `json:"commit"`
+}
+
+type commitResponse struct {
+	ID          string `json:"id"`
+	Message     string `json:"message"`
+	AuthorName  string `json:"author_name"`
+	AuthorEmail string `json:"author_email"`
+	AuthoredAt  string `json:"authored_date"`
+	WebURL      string `json:"web_url"`
+}
+
+type commitDetailResponse struct {
+	commitResponse
+	ParentIDs []string `json:"parent_ids"`
+	Stats     struct {
+		Additions int `json:"additions"`
+		Deletions int `json:"deletions"`
+	} `json:"stats"`
+}
+
+type compareResponse struct {
+	Commits []commitResponse `json:"commits"`
+	Diffs   []diffResponse   `json:"diffs"`
+}
+
+type diffResponse struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+	Diff        string `json:"diff"`
+}
+
+func New(baseURL, token string) *Client {
+	header := http.Header{}
+	if token != "" {
+		header.Set("PRIVATE-TOKEN", token)
+	}
+	return &Client{rest: forge.NewRESTClient(forge.GitLab, baseURL, header)}
+}
+
+func (c *Client) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	var repos []models.Repository
+	for page := 1; page > 0 && page <= maxPages; {
+		endpoint := fmt.Sprintf("projects?membership=true&order_by=last_activity_at&per_page=%d&page=%d", perPage, page)
+
+		var response []projectResponse
+		header, err := c.rest.Get(ctx, endpoint, &response)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range response {
+			repos = append(repos, mapProject(p))
+		}
+		page = nextPage(header)
+	}
+	return repos, nil
+}
+
+func (c *Client) GetRepository(ctx context.Context, nameWithOwner string) (models.Repository, error) {
+	var response projectResponse
+	if _, err := c.rest.Get(ctx, "projects/"+url.QueryEscape(nameWithOwner), &response); err != nil {
+		return models.Repository{}, fmt.Errorf("%s: %w", nameWithOwner, err)
+	}
+	return mapProject(response), nil
+}
+
+func (c *Client) SearchRepositories(ctx context.Context, query string) ([]models.Repository, error) {
+	endpoint := fmt.Sprintf("projects?search=%s&order_by=last_activity_at&per_page=%d", url.QueryEscape(query), searchPageSize)
+
+	var response []projectResponse
+	if _, err := c.rest.Get(ctx, endpoint, &response); err != nil {
+		return nil, err
+	}
+	repos := make([]models.Repository, len(response))
+	for i, p := range response {
+		repos[i] = mapProject(p)
+	}
+	return repos, nil
+}
+
+func (c *Client) ListBranches(ctx context.Context, repo models.Repository) ([]models.Branch, error) {
+	var branches []models.Branch
+	for page := 1; page > 0; {
+		endpoint := fmt.Sprintf("projects/%s/repository/branches?per_page=%d&page=%d", projectID(repo), perPage, page)
+
+		var response []branchResponse
+		header, err := c.rest.Get(ctx, endpoint, &response)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range response {
+			committedAt, _ := time.Parse(time.RFC3339, b.Commit.CommittedDate)
+			branches = append(branches, models.Branch{
+				Name:        b.Name,
+				SHA:         b.Commit.ID,
+				Protected:   b.Protected,
+				CommittedAt: committedAt,
+			})
+		}
+		page = nextPage(header)
+	}
+	models.SortBranchesByRecency(branches)
+	return branches, nil
+}
+
+func (c *Client) GetCommits(ctx context.Context, repo models.Repository, branch string, filters models.FilterOptions, page int) (models.CommitPage, error) {
+	var response []commitResponse
+	header, err := c.rest.Get(ctx, commitsEndpoint(repo, branch, filters, page), &response)
+	if err != nil {
+		return models.CommitPage{}, err
+	}
+
+	result := models.CommitPage{
+		Commits:  mapCommits(response),
+		Page:     page,
+		NextPage: nextPage(header),
+	}
+	result.LastPage, _ = strconv.Atoi(header.Get("X-Total-Pages"))
+	result.TotalCount, _ = strconv.Atoi(header.Get("X-Total"))
+	return result, nil
+}
+
+func (c *Client) GetCommit(ctx context.Context, repo models.Repository, sha string) (models.Commit, error) {
+	endpoint := fmt.Sprintf("projects/%s/repository/commits/%s", projectID(repo), url.PathEscape(sha))
+
+	var response commitDetailResponse
+	if _, err := c.rest.Get(ctx, endpoint, &response); err != nil {
+		return models.Commit{}, err
+	}
+
+	commit := mapCommits([]commitResponse{response.commitResponse})[0]
+	commit.Parents = response.ParentIDs
+	for page := 1; page > 0; {
+		var diffs []diffResponse
+		header, err := c.rest.Get(ctx, fmt.Sprintf("%s/diff?per_page=%d&page=%d", endpoint, perPage, page), &diffs)
+		if err != nil {
+			return models.Commit{}, err
+		}
+		for _, d := range diffs {
+			commit.Files = append(commit.Files, mapDiff(d))
+		}
+		page = nextPage(header)
+	}
+	commit.Stats = &models.CommitStats{Additions: response.Stats.Additions, Deletions: response.Stats.Deletions}
+	return commit, nil
+}
+
+func (c *Client) GetHistories(ctx context.Context, refs []forge.HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error) {
+	results := make([]models.RepoCommits, len(refs))
+	for i, ref := range refs {
+		if ref.Branch == "" && ref.Repository.DefaultBranchName == "" {
+			results[i] = forge.FailedHistory(ref, forge.ErrEmptyRepository)
+			continue
+		}
+
+		page, _ := strconv.Atoi(ref.Cursor)
+		result, err := c.GetCommits(ctx, ref.Repository, ref.Branch, filters, max(page, 1))
+		if err != nil {
+			results[i] = forge.FailedHistory(ref, err)
+			continue
+		}
+		results[i] = models.RepoCommits{
+			Repository: ref.Repository,
+			Branch:     ref.Branch,
+			Commits:    result.Commits,
+			HasMore:    result.HasMore(),
+			Page:       result.Page,
+			NextPage:   result.NextPage,
+			TotalCount: result.TotalCount,
+		}
+		if result.HasMore() {
+			results[i].Cursor = strconv.Itoa(result.NextPage)
+		}
+	}
+	return results, nil
+}
+
+func (c *Client) Compare(ctx context.Context, repo models.Repository, base, head string) (models.Comparison, error) {
+	ahead, err := c.compare(ctx, repo, base, head)
+	if err != nil {
+		return models.Comparison{}, err
+	}
+	behind, err := c.compare(ctx, repo, head, base)
+	if err != nil {
+		return models.Comparison{}, err
+	}
+
+	commits := mapCommits(ahead.Commits)
+	slices.Reverse(commits)
+	files := make([]models.FileChange, len(ahead.Diffs))
+	for i, d := range ahead.Diffs {
+		files[i] = mapDiff(d)
+	}
+	return models.Comparison{
+		Base:     base,
+		Head:     head,
+		Status:   models.CompareStatus(len(ahead.Commits), len(behind.Commits)),
+		AheadBy:  len(ahead.Commits),
+		BehindBy: len(behind.Commits),
+		Commits:  commits,
+		Files:    files,
+	}, nil
+}
+
+func (c *Client) compare(ctx context.Context, repo models.Repository, from, to string) (compareResponse, error) {
+	query := url.Values{}
+	query.Set("from", from)
+	query.Set("to", to)
+
+	var response compareResponse
+	_, err := c.rest.Get(ctx, fmt.Sprintf("projects/%s/repository/compare?%s", projectID(repo), query.Encode()), &response)
+	return response, err
+}
+
+func (c *Client) RateLimit() models.RateLimit {
+	return models.RateLimit{}
+}
+
+func commitsEndpoint(repo models.Repository, branch string, filters models.FilterOptions, page int) string {
+	query := url.Values{}
+	query.Set("per_page", strconv.Itoa(filters.PerPage))
+	query.Set("page", strconv.Itoa(page))
+	if branch != "" {
+		query.Set("ref_name", branch)
+	}
+	if filters.DateFrom != "" {
+		query.Set("since", filters.DateFrom+"T00:00:00Z")
+	}
+	if filters.DateTo != "" {
+		query.Set("until", filters.DateTo+"T23:59:59Z")
+	}
+	if filters.Author != "" {
+		query.Set("author", filters.Author)
+	}
+	return fmt.Sprintf("projects/%s/repository/commits?%s", projectID(repo), query.Encode())
+}
+
+func projectID(repo models.Repository) string {
+	return url.QueryEscape(repo.NameWithOwner)
+}
+
+func nextPage(header http.Header) int {
+	if next, err := strconv.Atoi(header.Get("X-Next-Page")); err == nil {
+		return next
+	}
+	return forge.ParsePageLinks(header.Get("Link")).Next
+}
+
+func mapProject(p projectResponse) models.Repository {
+	pushedAt, _ := time.Parse(time.RFC3339, p.LastActivityAt)
+	return models.Repository{
+		Name:              p.Path,
+		NameWithOwner:     p.PathWithNamespace,
+		Description:       p.Description,
+		URL:               p.WebURL,
+		PushedAt:          pushedAt,
+		DefaultBranchName: p.DefaultBranch,
+		IsFork:            len(p.ForkedFrom) > 0 && string(p.ForkedFrom) != "null",
+		IsArchived:        p.Archived,
+		IsPrivate:         p.Visibility != "" && p.Visibility != "public",
+		Visibility:        p.Visibility,
+		Stars:             p.StarCount,
+	}
+}
+
+func mapDiff(d diffResponse) models.FileChange {
+	file := models.FileChange{Filename: d.NewPath, Status: "modified"}
+	switch {
+	case d.NewFile:
+		file.Status = "added"
+	case d.DeletedFile:
+		file.Status = "removed"
+	case d.RenamedFile:
+		file.Status = "renamed"
+		file.PreviousFilename = d.OldPath
+	}
+	inHunk := false
+	for _, line := range strings.Split(d.Diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			inHunk = true
+		case !inHunk:
+		case strings.HasPrefix(line, "+"):
+			file.Additions++
+		case strings.HasPrefix(line, "-"):
+			file.Deletions++
+		}
+	}
+	return file
+}
+
+func mapCommits(response []commitResponse) []models.Commit {
+	commits := make([]models.Commit, len(response))
+	for i, c := range response {
+		date, _ := time.Parse(time.RFC3339, c.AuthoredAt)
+		commits[i] = models.Commit{
+			SHA:     c.ID,
+			Message: c.Message,
+			Author:  c.AuthorName,
+			Email:   c.AuthorEmail,
+			Date:    date,
+			URL:     c.WebURL,
+		}
+	}
+	return commits
+}
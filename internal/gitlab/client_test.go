@@ -0,0 +1,250 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+var gateway = models.Repository{NameWithOwner: "payments/gateway", DefaultBranchName: "main"}
+
+func newFixtureClient(t *testing.T, handler func(w http.ResponseWriter, r *http.Request)) *Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("PRIVATE-TOKEN") != "test-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message":"401 Unauthorized"}`))
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return New(server.URL+"/api/v4", "test-token")
+}
+
+func serveFixture(t *testing.T, w http.ResponseWriter, name string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture: %v", err)
+	}
+	w.Write(data)
+}
+
+func TestListRepositories(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v4/projects" || r.URL.Query().Get("membership") != "true" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		serveFixture(t, w, "projects.json")
+	})
+
+	repos, err := client.ListRepositories(context.Background())
+	if err != nil {
+		t.Fatalf("ListRepositories() error: %v", err)
+	}
+	if len(repos) != 2 {
+		t.Fatalf("repos = %+v", repos)
+	}
+	if r := repos[0]; r.NameWithOwner != "payments/gateway" || r.Name != "gateway" || r.DefaultBranchName != "main" || r.PushedAt.IsZero() {
+		t.Errorf("repos[0] = %+v", r)
+	}
+	if r := repos[1]; r.Owner() != "payments" || r.RepoName() != "tools/scratch" || r.DefaultBranchName != "" {
+		t.Errorf("repos[1] = %+v", r)
+	}
+	if r := repos[0]; !r.IsPrivate || r.IsFork || r.Stars != 4 {
+		t.Errorf("repos[0] metadata = %+v", r)
+	}
+	if r := repos[1]; !r.IsFork || !r.IsArchived || r.Visibility != "internal" {
+		t.Errorf("repos[1] metadata = %+v", r)
+	}
+}
+
+func TestListBranches(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/payments%2Fgateway/repository/branches" {
+			t.Errorf("unexpected path %s", r.URL.EscapedPath())
+		}
+		serveFixture(t, w, "branches.json")
+	})
+
+	branches, err := client.ListBranches(context.Background(), gateway)
+	if err != nil {
+		t.Fatalf("ListBranches() error: %v", err)
+	}
+	if len(branches) != 2 || branches[0].Name != "release/2.4" || !branches[0].Protected || branches[1].SHA == "" {
+		t.Errorf("branches = %+v", branches)
+	}
+}
+
+func TestGetHistories(t *testing.T) {
+	var query map[string][]string
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("X-Next-Page", "2")
+		w.Header().Set("X-Total", "57")
+		serveFixture(t, w, "commits.json")
+	})
+
+	refs := []forge.HistoryRef{{Repository: gateway, Branch: "main"}}
+	filters := models.FilterOptions{PerPage: 2, DateFrom: "2024-06-01", Author: "dana"}
+	results, err := client.GetHistories(context.Background(), refs, filters)
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+
+	rc := results[0]
+	if rc.Failed() || len(rc.Commits) != 2 || !rc.HasMore || rc.Cursor != "2" || rc.TotalCount != 57 {
+		t.Fatalf("result = %+v", rc)
+	}
+	c := rc.Commits[0]
+	if c.Author != "Dana Reyes" || c.Email != "dana@example.com" || c.FirstLine() != "Retry declined card authorizations" || c.Date.IsZero() {
+		t.Errorf("commit = %+v", c)
+	}
+	for key, want := range map[string]string{"ref_name": "main", "since": "2024-06-01T00:00:00Z", "author": "dana", "per_page": "2"} {
+		if got := query[key]; len(got) != 1 || got[0] != want {
+			t.Errorf("query %s = %v, want %s", key, got, want)
+		}
+	}
+}
+
+func TestGetHistoriesErrors(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"404 Project Not Found"}`))
+	})
+
+	refs := []forge.HistoryRef{
+		{Repository: models.Repository{NameWithOwner: "payments/gone", DefaultBranchName: "main"}, Branch: "main"},
+		{Repository: models.Repository{NameWithOwner: "payments/tools/scratch"}},
+	}
+	results, err := client.GetHistories(context.Background(), refs, models.FilterOptions{PerPage: 10})
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+	if !errors.Is(results[0].Err, forge.ErrNotFound) {
+		t.Errorf("results[0].Err = %v, want ErrNotFound", results[0].Err)
+	}
+	if !errors.Is(results[1].Err, forge.ErrEmptyRepository) {
+		t.Errorf("results[1].Err = %v, want ErrEmptyRepository", results[1].Err)
+	}
+}
+
+func TestGetRepositoryAndSearch(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.EscapedPath() == "/api/v4/projects/payments%2Fgateway":
+			w.Write([]byte(`{"path": "gateway", "path_with_namespace": "payments/gateway", "default_branch": "main"}`))
+		case r.URL.Path == "/api/v4/projects" && r.URL.Query().Get("search") == "gate":
+			serveFixture(t, w, "projects.json")
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	repo, err := client.GetRepository(context.Background(), "payments/gateway")
+	if err != nil || repo.NameWithOwner != "payments/gateway" {
+		t.Errorf("GetRepository() = %+v, %v", repo, err)
+	}
+	repos, err := client.SearchRepositories(context.Background(), "gate")
+	if err != nil || len(repos) != 2 {
+		t.Errorf("SearchRepositories() = %+v, %v", repos, err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/payments%2Fgateway/repository/compare" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if r.URL.Query().Get("from") == "main" && r.URL.Query().Get("to") == "release/1.2" {
+			serveFixture(t, w, "compare.json")
+			return
+		}
+		w.Write([]byte(`{"commits": [{"id": "c0ffee"}], "diffs": []}`))
+	})
+
+	comparison, err := client.Compare(context.Background(), gateway, "main", "release/1.2")
+	if err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+	if comparison.AheadBy != 2 || comparison.BehindBy != 1 || comparison.Status != models.CompareDiverged {
+		t.Errorf("comparison = %+v", comparison)
+	}
+	if len(comparison.Commits) != 2 || comparison.Commits[0].Author != "Dana Reyes" {
+		t.Errorf("commits = %+v, want newest first", comparison.Commits)
+	}
+
+	tests := []struct {
+		name      string
+		status    string
+		previous  string
+		additions int
+		deletions int
+	}{
+		{"client/http.go", "modified", "", 1, 1},
+		{"payments/retry.go", "added", "", 3, 0},
+		{"docs/declines.md", "renamed", "docs/retries.md", 0, 0},
+	}
+	if len(comparison.Files) != len(tests) {
+		t.Fatalf("files = %+v", comparison.Files)
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := comparison.Files[i]
+			if f.Filename != tt.name || f.Status != tt.status || f.PreviousFilename != tt.previous || f.Additions != tt.additions || f.Deletions != tt.deletions {
+				t.Errorf("file = %+v", f)
+			}
+		})
+	}
+}
+
+func TestGetCommit(t *testing.T) {
+	client := newFixtureClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/payments%2Fgateway/repository/commits/7b5c3cc8":
+			w.Write([]byte(`{
+				"id": "7b5c3cc8", "message": "Retry declined card authorizations\n", "author_name": "Dana Reyes",
+				"parent_ids": ["9d1f2e6b"], "stats": {"additions": 4, "deletions": 1, "total": 5}
+			}`))
+		case "/api/v4/projects/payments%2Fgateway/repository/commits/7b5c3cc8/diff":
+			if r.URL.Query().Get("page") == "2" {
+				w.Write([]byte(`[
+					{"old_path": "deploy.yaml", "new_path": "deploy.yaml", "diff": "@@ -1,3 +1,2 @@\n----\n-- name: api\n+++ counter\n"}
+				]`))
+				return
+			}
+			w.Header().Set("X-Next-Page", "2")
+			w.Write([]byte(`[
+				{"old_path": "client/http.go", "new_path": "client/http.go", "diff": "@@ -1 +1 @@\n-a\n+b\n"},
+				{"old_path": "retry.go", "new_path": "retry.go", "new_file": true, "diff": "@@ -0,0 +1,3 @@\n+a\n+b\n+c\n"}
+			]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	})
+
+	commit, err := client.GetCommit(context.Background(), gateway, "7b5c3cc8")
+	if err != nil {
+		t.Fatalf("GetCommit() error: %v", err)
+	}
+	if commit.Author != "Dana Reyes" || len(commit.Parents) != 1 || commit.Parents[0] != "9d1f2e6b" {
+		t.Errorf("commit = %+v", commit)
+	}
+	if !commit.HasDetail() || commit.Stats.Additions != 4 || commit.Stats.Deletions != 1 {
+		t.Errorf("stats = %+v", commit.Stats)
+	}
+	if len(commit.Files) != 3 || commit.Files[1].Status != "added" || commit.Files[1].Additions != 3 {
+		t.Errorf("files = %+v", commit.Files)
+	}
+	if yaml := commit.Files[2]; yaml.Additions != 1 || yaml.Deletions != 2 {
+		t.Errorf("deploy.yaml = +%d -%d, want lines starting with --- and +++ counted", yaml.Additions, yaml.Deletions)
+	}
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+type Branch struct {
+	Name        string    `json:"name"`
+	SHA         string    `json:"sha"`
+	Protected   bool      `json:"protected"`
+	CommittedAt time.Time `json:"committedAt"`
+}
+
+func BranchNames(branches []Branch) []string {
+	names := make([]string, len(branches))
+	for i, b := range branches {
+		names[i] = b.Name
+	}
+	return names
+}
+
+func SortBranchesByRecency(branches []Branch) {
+	sort.SliceStable(branches, func(i, j int) bool {
+		return branches[i].CommittedAt.After(branches[j].CommittedAt)
+	})
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBranchNames(t *testing.T) {
+	branches := []Branch{{Name: "main"}, {Name: "dev"}}
+
+	names := BranchNames(branches)
+	if len(names) != 2 || names[0] != "main" || names[1] != "dev" {
+		t.Errorf("BranchNames() = %v, want [main dev]", names)
+	}
+}
+
+func TestSortBranchesByRecency(t *testing.T) {
+	now := time.Now()
+	branches := []Branch{
+		{Name: "old", CommittedAt: now.Add(-48 * time.Hour)},
+		{Name: "unknown"},
+		{Name: "new", CommittedAt: now},
+		{Name: "mid", CommittedAt: now.Add(-time.Hour)},
+	}
+
+	SortBranchesByRecency(branches)
+
+	expected := []string{"new", "mid", "old", "unknown"}
+	for i, name := range expected {
+		if branches[i].Name != name {
+			t.Errorf("branches[%d] = %q, want %q", i, branches[i].Name, name)
+		}
+	}
+}
@@ -2,28 +2,94 @@ package models
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
 
 type Commit struct {
-	SHA     string    `json:"sha"`
-	Message string    `json:"message"`
-	Author  string    `json:"author"`
-	Email   string    `json:"email"`
-	Date    time.Time `json:"date"`
-	URL     string    `json:"url"`
+	SHA      string       `json:"sha"`
+	Message  string       `json:"message"`
+	Author   string       `json:"author"`
+	Email    string       `json:"email"`
+	Login    string       `json:"login,omitempty"`
+	Date     time.Time    `json:"date"`
+	URL      string       `json:"url"`
+	Branches []string     `json:"branches,omitempty"`
+	Parents  []string     `json:"parents,omitempty"`
+	Files    []FileChange `json:"files,omitempty"`
+	Stats    *CommitStats `json:"stats,omitempty"`
 }
 
+type CommitStats struct {
+	Additions int `json:"additions"`
+	Deletions int `json:"deletions"`
+}
+
+type RepoStatus int
+
+const (
+	RepoStatusLoaded RepoStatus = iota
+	RepoStatusLoading
+	RepoStatusFailed
+)
+
 type RepoCommits struct {
-	Repository Repository
-	Branch     string
+	Repository  Repository
+	Status      RepoStatus
+	Err         error
+	Branch      string
+	Branches    []string
+	AllBranches bool
+	Commits     []Commit
+	HasMore     bool
+	Page        int
+	NextPage    int
+	Cursor      string
+	Cursors     map[string]string
+	Unmerged    map[string]bool
+	TotalCount  int
+	SyncedAt    time.Time
+	Partial     bool
+}
+
+func (rc RepoCommits) Failed() bool {
+	return rc.Status == RepoStatusFailed
+}
+
+func (rc RepoCommits) MultiBranch() bool {
+	return rc.AllBranches || len(rc.Branches) > 1
+}
+
+func (rc RepoCommits) BranchLabel() string {
+	switch {
+	case rc.AllBranches && len(rc.Branches) > 0:
+		return fmt.Sprintf("all branches: %d", len(rc.Branches))
+	case rc.AllBranches:
+		return "all branches"
+	case rc.MultiBranch():
+		return strings.Join(rc.Branches, ", ")
+	default:
+		return rc.Branch
+	}
+}
+
+func (rc RepoCommits) IsUnmerged(c Commit) bool {
+	return rc.AllBranches && rc.Unmerged[c.SHA]
+}
+
+type CommitPage struct {
 	Commits    []Commit
-	HasMore    bool
 	Page       int
+	NextPage   int
+	LastPage   int
 	TotalCount int
 }
 
+func (p CommitPage) HasMore() bool {
+	return p.NextPage > 0
+}
+
 func (c Commit) ShortSHA() string {
 	if len(c.SHA) >= 7 {
 		return c.SHA[:7]
@@ -54,9 +120,62 @@ func (c Commit) FormattedDate() string {
 	return c.Date.Format("2006-01-02 15:04")
 }
 
+func (c Commit) HasDetail() bool {
+	return c.Stats != nil
+}
+
+func (c Commit) WithDetail(detail Commit) Commit {
+	c.Parents = detail.Parents
+	c.Files = detail.Files
+	c.Stats = detail.Stats
+	return c
+}
+
 func (c Commit) AuthorWithEmail() string {
 	if c.Email != "" {
 		return fmt.Sprintf("%s <%s>", c.Author, c.Email)
 	}
 	return c.Author
 }
+
+func SetCommitDetail(repoCommits []RepoCommits, repoID string, detail Commit) []RepoCommits {
+	updated := slices.Clone(repoCommits)
+	for i, rc := range updated {
+		if rc.Repository.ID() != repoID {
+			continue
+		}
+		for j, c := range rc.Commits {
+			if c.SHA == detail.SHA {
+				updated[i].Commits = slices.Clone(rc.Commits)
+				updated[i].Commits[j] = c.WithDetail(detail)
+				return updated
+			}
+		}
+	}
+	return updated
+}
+
+func MergeCommits(current, more []Commit) []Commit {
+	merged := slices.Clone(current)
+	index := make(map[string]int, len(merged))
+	for i, c := range merged {
+		index[c.SHA] = i
+	}
+
+	for _, c := range more {
+		i, ok := index[c.SHA]
+		if !ok {
+			index[c.SHA] = len(merged)
+			merged = append(merged, c)
+			continue
+		}
+		for _, branch := range c.Branches {
+			if !slices.Contains(merged[i].Branches, branch) {
+				merged[i].Branches = append(slices.Clip(merged[i].Branches), branch)
+			}
+		}
+	}
+
+	slices.SortStableFunc(merged, func(a, b Commit) int { return b.Date.Compare(a.Date) })
+	return merged
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -128,3 +130,106 @@ func TestCommitAuthorWithEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestCommitPageHasMore(t *testing.T) {
+	tests := []struct {
+		name     string
+		nextPage int
+		expected bool
+	}{
+		{"lastPage", 0, false},
+		{"hasNext", 2, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := CommitPage{NextPage: tt.nextPage}
+			if got := p.HasMore(); got != tt.expected {
+				t.Errorf("HasMore() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMergeCommits(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	current := []Commit{
+		{SHA: "c3", Date: day(3), Branches: []string{"main"}},
+		{SHA: "c1", Date: day(1), Branches: []string{"main"}},
+	}
+	more := []Commit{
+		{SHA: "c4", Date: day(4), Branches: []string{"release"}},
+		{SHA: "c3", Date: day(3), Branches: []string{"release"}},
+		{SHA: "c2", Date: day(2), Branches: []string{"release"}},
+	}
+
+	merged := MergeCommits(current, more)
+
+	var got []string
+	for _, c := range merged {
+		got = append(got, c.SHA+":"+strings.Join(c.Branches, ","))
+	}
+	want := []string{"c4:release", "c3:main,release", "c2:release", "c1:main"}
+	if !slices.Equal(got, want) {
+		t.Errorf("MergeCommits() = %v, want %v", got, want)
+	}
+	if len(current[0].Branches) != 1 {
+		t.Errorf("MergeCommits should not modify its input, got %v", current[0].Branches)
+	}
+}
+
+func TestSetCommitDetail(t *testing.T) {
+	current := []RepoCommits{
+		{Repository: Repository{NameWithOwner: "owner/one"}, Commits: []Commit{{SHA: "a1"}}},
+		{Repository: Repository{NameWithOwner: "owner/two"}, Commits: []Commit{{SHA: "a1", Message: "fix"}, {SHA: "b2"}}},
+	}
+	detail := Commit{SHA: "a1", Parents: []string{"p1"}, Files: []FileChange{{Filename: "main.go"}}, Stats: &CommitStats{Additions: 3}}
+
+	updated := SetCommitDetail(current, "owner/two", detail)
+
+	if updated[0].Commits[0].HasDetail() {
+		t.Error("owner/one should not get the detail")
+	}
+	if c := updated[1].Commits[0]; !c.HasDetail() || c.Message != "fix" || len(c.Files) != 1 || c.Parents[0] != "p1" {
+		t.Errorf("owner/two commit = %+v", c)
+	}
+	if current[1].Commits[0].HasDetail() {
+		t.Error("SetCommitDetail should not modify its input")
+	}
+}
+
+func TestRepoCommitsBranchLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		rc       RepoCommits
+		expected string
+	}{
+		{"single", RepoCommits{Branch: "main"}, "main"},
+		{"singleInList", RepoCommits{Branch: "main", Branches: []string{"main"}}, "main"},
+		{"multiple", RepoCommits{Branch: "main", Branches: []string{"main", "release/1.2"}}, "main, release/1.2"},
+		{"allPending", RepoCommits{Branch: "main", AllBranches: true}, "all branches"},
+		{"allLoaded", RepoCommits{Branch: "main", AllBranches: true, Branches: []string{"main", "dev", "fix"}}, "all branches: 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rc.BranchLabel(); got != tt.expected {
+				t.Errorf("BranchLabel() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepoCommitsIsUnmerged(t *testing.T) {
+	rc := RepoCommits{Repository: Repository{DefaultBranchName: "main"}, AllBranches: true, Unmerged: map[string]bool{"wip": true}}
+	merged := Commit{SHA: "old", Branches: []string{"feature"}}
+	unmerged := Commit{SHA: "wip", Branches: []string{"feature"}}
+
+	if rc.IsUnmerged(merged) || !rc.IsUnmerged(unmerged) {
+		t.Errorf("IsUnmerged() = %v, %v, want false, true", rc.IsUnmerged(merged), rc.IsUnmerged(unmerged))
+	}
+	rc.AllBranches = false
+	if rc.IsUnmerged(unmerged) {
+		t.Error("commits are only flagged in all-branches mode")
+	}
+}
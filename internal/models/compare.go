@@ -0,0 +1,106 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	CompareAhead     = "ahead"
+	CompareBehind    = "behind"
+	CompareDiverged  = "diverged"
+	CompareIdentical = "identical"
+)
+
+type FileChange struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previousFilename,omitempty"`
+	Status           string `json:"status"`
+	Additions        int    `json:"additions"`
+	Deletions        int    `json:"deletions"`
+}
+
+type Comparison struct {
+	Base     string       `json:"base"`
+	Head     string       `json:"head"`
+	Status   string       `json:"status"`
+	AheadBy  int          `json:"aheadBy"`
+	BehindBy int          `json:"behindBy"`
+	Commits  []Commit     `json:"commits"`
+	Files    []FileChange `json:"files"`
+}
+
+func ParseCompareSpec(spec, defaultBase string) (base, head string, err error) {
+	base, head, ok := strings.Cut(spec, "...")
+	if !ok {
+		base, head = defaultBase, spec
+	}
+	base, head = strings.TrimSpace(base), strings.TrimSpace(head)
+	if base == "" || head == "" {
+		return "", "", fmt.Errorf("invalid comparison %q, expected base...head", spec)
+	}
+	return base, head, nil
+}
+
+func CompareStatus(aheadBy, behindBy int) string {
+	switch {
+	case aheadBy > 0 && behindBy > 0:
+		return CompareDiverged
+	case aheadBy > 0:
+		return CompareAhead
+	case behindBy > 0:
+		return CompareBehind
+	default:
+		return CompareIdentical
+	}
+}
+
+func (c Comparison) Spec() string {
+	return c.Base + "..." + c.Head
+}
+
+func (c Comparison) Additions() int {
+	total := 0
+	for _, f := range c.Files {
+		total += f.Additions
+	}
+	return total
+}
+
+func (c Comparison) Deletions() int {
+	total := 0
+	for _, f := range c.Files {
+		total += f.Deletions
+	}
+	return total
+}
+
+func (c Comparison) Summary() string {
+	counts := fmt.Sprintf("%d ahead, %d behind", c.AheadBy, c.BehindBy)
+	if len(c.Files) == 0 {
+		return counts
+	}
+	return counts + " • " + ChangeSummary(len(c.Files), c.Additions(), c.Deletions())
+}
+
+func ChangeSummary(files, additions, deletions int) string {
+	summary := fmt.Sprintf("%d files changed", files)
+	if files == 1 {
+		summary = "1 file changed"
+	}
+	if additions == 0 && deletions == 0 {
+		return summary
+	}
+	return fmt.Sprintf("%s, +%d -%d", summary, additions, deletions)
+}
+
+func (f FileChange) Renamed() bool {
+	return f.PreviousFilename != "" && f.PreviousFilename != f.Filename
+}
+
+func (f FileChange) Path() string {
+	if f.Renamed() {
+		return f.PreviousFilename + " → " + f.Filename
+	}
+	return f.Filename
+}
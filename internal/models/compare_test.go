@@ -0,0 +1,71 @@
+package models
+
+import "testing"
+
+func TestParseCompareSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		base    string
+		head    string
+		wantErr bool
+	}{
+		{"threeDots", "main...release/1.2", "main", "release/1.2", false},
+		{"headOnly", "release/1.2", "main", "release/1.2", false},
+		{"spaces", " main ... release ", "main", "release", false},
+		{"missingHead", "main...", "", "", true},
+		{"empty", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, head, err := ParseCompareSpec(tt.spec, "main")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCompareSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if base != tt.base || head != tt.head {
+				t.Errorf("ParseCompareSpec() = %q, %q, want %q, %q", base, head, tt.base, tt.head)
+			}
+		})
+	}
+}
+
+func TestCompareStatus(t *testing.T) {
+	tests := []struct {
+		ahead, behind int
+		expected      string
+	}{
+		{3, 0, CompareAhead},
+		{0, 2, CompareBehind},
+		{3, 2, CompareDiverged},
+		{0, 0, CompareIdentical},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			if got := CompareStatus(tt.ahead, tt.behind); got != tt.expected {
+				t.Errorf("CompareStatus(%d, %d) = %q, want %q", tt.ahead, tt.behind, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestComparisonSummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		comparison Comparison
+		expected   string
+	}{
+		{"noFiles", Comparison{AheadBy: 0, BehindBy: 4}, "0 ahead, 4 behind"},
+		{"lineStats", Comparison{AheadBy: 2, Files: []FileChange{{Additions: 5, Deletions: 1}, {Additions: 2}}}, "2 ahead, 0 behind • 2 files changed, +7 -1"},
+		{"statusOnly", Comparison{AheadBy: 1, Files: []FileChange{{Status: "added"}}}, "1 ahead, 0 behind • 1 file changed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.comparison.Summary(); got != tt.expected {
+				t.Errorf("Summary() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -6,11 +6,12 @@ const (
 )
 
 type FilterOptions struct {
-	DateFrom      string
-	DateTo        string
-	Author        string
-	PerPage       int
-	SemanticQuery string
+	DateFrom      string `json:"dateFrom,omitempty"`
+	DateTo        string `json:"dateTo,omitempty"`
+	Author        string `json:"author,omitempty"`
+	PerPage       int    `json:"perPage,omitempty"`
+	SemanticQuery string `json:"semanticQuery,omitempty"`
+	AllBranches   bool   `json:"allBranches,omitempty"`
 }
 
 type BranchSelection struct {
@@ -35,6 +36,10 @@ func (f FilterOptions) HasAnyFilter() bool {
 	return f.hasDateFilter() || f.hasAuthorFilter() || f.HasSemanticFilter()
 }
 
+func (f FilterOptions) HasHistoryFilter() bool {
+	return f.hasDateFilter() || f.hasAuthorFilter()
+}
+
 func (f FilterOptions) HasSemanticFilter() bool {
 	return f.SemanticQuery != ""
 }
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type RateLimit struct {
+	Resource  string    `json:"resource"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset"`
+}
+
+func (r RateLimit) Known() bool {
+	return r.Limit > 0
+}
+
+func (r RateLimit) IsLow() bool {
+	return r.Known() && r.Remaining*10 < r.Limit
+}
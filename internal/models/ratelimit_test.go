@@ -0,0 +1,25 @@
+package models
+
+import "testing"
+
+func TestRateLimitIsLow(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    RateLimit
+		expected bool
+	}{
+		{"unknown", RateLimit{}, false},
+		{"plenty", RateLimit{Limit: 5000, Remaining: 4000}, false},
+		{"atThreshold", RateLimit{Limit: 5000, Remaining: 500}, false},
+		{"low", RateLimit{Limit: 5000, Remaining: 499}, true},
+		{"exhausted", RateLimit{Limit: 5000, Remaining: 0}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.IsLow(); got != tt.expected {
+				t.Errorf("IsLow() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
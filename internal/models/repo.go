@@ -2,17 +2,59 @@ package models
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 )
 
+type RepoSource string
+
+const (
+	SourceOwner        RepoSource = "owner"
+	SourceOrg          RepoSource = "org"
+	SourceTeam         RepoSource = "team"
+	SourceCollaborator RepoSource = "collaborator"
+	SourceStarred      RepoSource = "starred"
+	SourceSaved        RepoSource = "saved"
+)
+
+var RepoSources = []RepoSource{SourceOwner, SourceOrg, SourceTeam, SourceCollaborator, SourceStarred}
+
 type Repository struct {
-	Name              string    `json:"name"`
-	NameWithOwner     string    `json:"nameWithOwner"`
-	Description       string    `json:"description"`
-	URL               string    `json:"url"`
-	PushedAt          time.Time `json:"pushedAt"`
-	DefaultBranchName string    `json:"defaultBranchName"`
+	Host              string       `json:"host,omitempty"`
+	Name              string       `json:"name"`
+	NameWithOwner     string       `json:"nameWithOwner"`
+	Description       string       `json:"description"`
+	URL               string       `json:"url"`
+	PushedAt          time.Time    `json:"pushedAt"`
+	DefaultBranchName string       `json:"defaultBranchName"`
+	IsFork            bool         `json:"isFork,omitempty"`
+	IsArchived        bool         `json:"isArchived,omitempty"`
+	IsPrivate         bool         `json:"isPrivate,omitempty"`
+	Visibility        string       `json:"visibility,omitempty"`
+	PrimaryLanguage   string       `json:"primaryLanguage,omitempty"`
+	Stars             int          `json:"stars,omitempty"`
+	Sources           []RepoSource `json:"sources,omitempty"`
+}
+
+func (r Repository) ID() string {
+	if r.Host == "" {
+		return r.NameWithOwner
+	}
+	return r.Host + "/" + r.NameWithOwner
+}
+
+func (r Repository) HasSource(source RepoSource) bool {
+	return slices.Contains(r.Sources, source)
+}
+
+func ParseRepoSource(s string) (RepoSource, error) {
+	for _, source := range RepoSources {
+		if string(source) == s {
+			return source, nil
+		}
+	}
+	return "", fmt.Errorf("unknown repository source %q", s)
 }
 
 func (r Repository) Owner() string {
@@ -26,11 +68,40 @@ func (r Repository) RepoName() string {
 	return r.Name
 }
 
+func (r Repository) Tags() []string {
+	var tags []string
+	if visibility := r.visibility(); visibility != "public" {
+		tags = append(tags, visibility)
+	}
+	if r.IsFork {
+		tags = append(tags, "fork")
+	}
+	if r.IsArchived {
+		tags = append(tags, "archived")
+	}
+	return tags
+}
+
+func (r Repository) visibility() string {
+	switch {
+	case r.Visibility != "":
+		return strings.ToLower(r.Visibility)
+	case r.IsPrivate:
+		return "private"
+	default:
+		return "public"
+	}
+}
+
 func (r Repository) TimeSincePush() string {
-	if r.PushedAt.IsZero() {
+	return Age(r.PushedAt)
+}
+
+func Age(t time.Time) string {
+	if t.IsZero() {
 		return "unknown"
 	}
-	return formatDuration(time.Since(r.PushedAt))
+	return formatDuration(time.Since(t))
 }
 
 func splitNameWithOwner(nameWithOwner string, index int) string {
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"slices"
 	"testing"
 	"time"
 )
@@ -79,3 +80,49 @@ func TestRepositoryTimeSincePush(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRepoSource(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected RepoSource
+		wantErr  bool
+	}{
+		{"owner", SourceOwner, false},
+		{"starred", SourceStarred, false},
+		{"team", SourceTeam, false},
+		{"forks", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseRepoSource(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRepoSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseRepoSource() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRepositoryTags(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     Repository
+		expected []string
+	}{
+		{"public", Repository{}, nil},
+		{"privateFlag", Repository{IsPrivate: true}, []string{"private"}},
+		{"internalVisibility", Repository{IsPrivate: true, Visibility: "INTERNAL"}, []string{"internal"}},
+		{"archivedFork", Repository{IsFork: true, IsArchived: true, Visibility: "public"}, []string{"fork", "archived"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.repo.Tags(); !slices.Equal(got, tt.expected) {
+				t.Errorf("Tags() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,381 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const (
+	maxSyncPages     = 10
+	maxBackfillPages = 10
+)
+
+const repositoriesKey = "repositories"
+
+var syncFilters = models.FilterOptions{PerPage: models.MaxPerPage}
+
+type Client struct {
+	remote forge.Provider
+	store  *Store
+	now    func() time.Time
+}
+
+func NewClient(remote forge.Provider, store *Store) *Client {
+	return &Client{remote: remote, store: store, now: time.Now}
+}
+
+func NewOfflineClient(store *Store) *Client {
+	return &Client{store: store, now: time.Now}
+}
+
+func (c *Client) offline() bool {
+	return c.remote == nil
+}
+
+func (c *Client) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	var repos []models.Repository
+	if c.offline() {
+		_, err := c.store.Snapshot(ctx, repositoriesKey, &repos)
+		return repos, err
+	}
+
+	repos, err := c.remote.ListRepositories(ctx)
+	if err != nil {
+		return repos, err
+	}
+	_ = c.store.PutSnapshot(ctx, repositoriesKey, repos, c.now())
+	return repos, nil
+}
+
+func (c *Client) GetRepository(ctx context.Context, nameWithOwner string) (models.Repository, error) {
+	var repo models.Repository
+	key := "repository:" + nameWithOwner
+	if c.offline() {
+		_, err := c.store.Snapshot(ctx, key, &repo)
+		return repo, err
+	}
+
+	repo, err := c.remote.GetRepository(ctx, nameWithOwner)
+	if err != nil {
+		return repo, err
+	}
+	_ = c.store.PutSnapshot(ctx, key, repo, c.now())
+	return repo, nil
+}
+
+func (c *Client) SearchRepositories(ctx context.Context, query string) ([]models.Repository, error) {
+	if !c.offline() {
+		return c.remote.SearchRepositories(ctx, query)
+	}
+
+	var cached []models.Repository
+	if _, err := c.store.Snapshot(ctx, repositoriesKey, &cached); err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(query)
+	var repos []models.Repository
+	for _, repo := range cached {
+		if strings.Contains(strings.ToLower(repo.ID()), query) {
+			repos = append(repos, repo)
+		}
+	}
+	return repos, nil
+}
+
+func (c *Client) ListBranches(ctx context.Context, repo models.Repository) ([]models.Branch, error) {
+	var branches []models.Branch
+	key := "branches:" + repo.ID()
+	if c.offline() {
+		_, err := c.store.Snapshot(ctx, key, &branches)
+		return branches, err
+	}
+
+	branches, err := c.remote.ListBranches(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.store.PutSnapshot(ctx, key, branches, c.now())
+	return branches, nil
+}
+
+func (c *Client) GetCommit(ctx context.Context, repo models.Repository, sha string) (models.Commit, error) {
+	var commit models.Commit
+	key := "commit:" + repo.ID() + "@" + sha
+	if _, err := c.store.Snapshot(ctx, key, &commit); err == nil || c.offline() {
+		return commit, err
+	}
+
+	commit, err := c.remote.GetCommit(ctx, repo, sha)
+	if err != nil {
+		return commit, err
+	}
+	_ = c.store.PutSnapshot(ctx, key, commit, c.now())
+	return commit, nil
+}
+
+func (c *Client) Compare(ctx context.Context, repo models.Repository, base, head string) (models.Comparison, error) {
+	var comparison models.Comparison
+	key := "compare:" + repo.ID() + ":" + base + "..." + head
+	if c.offline() {
+		_, err := c.store.Snapshot(ctx, key, &comparison)
+		return comparison, err
+	}
+
+	comparison, err := c.remote.Compare(ctx, repo, base, head)
+	if err != nil {
+		return comparison, err
+	}
+	_ = c.store.PutSnapshot(ctx, key, comparison, c.now())
+	return comparison, nil
+}
+
+func (c *Client) RateLimit() models.RateLimit {
+	if c.offline() {
+		return models.RateLimit{}
+	}
+	return c.remote.RateLimit()
+}
+
+func (c *Client) GetHistories(ctx context.Context, refs []forge.HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error) {
+	var unsynced []forge.HistoryRef
+	for _, ref := range refs {
+		if ref.Cursor == "" {
+			unsynced = append(unsynced, ref)
+		}
+	}
+
+	failed := make(map[string]error)
+	if !c.offline() {
+		var err error
+		if failed, err = c.sync(ctx, unsynced); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]models.RepoCommits, len(refs))
+	for i, ref := range refs {
+		if err := failed[refKey(ref)]; err != nil {
+			results[i] = forge.FailedHistory(ref, err)
+			continue
+		}
+		offset, _ := strconv.Atoi(ref.Cursor)
+		rc, err := c.page(ctx, ref, filters, offset)
+		if err != nil {
+			results[i] = forge.FailedHistory(ref, err)
+			continue
+		}
+		results[i] = rc
+	}
+	return results, nil
+}
+
+func (c *Client) GetCommits(ctx context.Context, repo models.Repository, branch string, filters models.FilterOptions, page int) (models.CommitPage, error) {
+	ref := forge.HistoryRef{Repository: repo, Branch: branch}
+	if page <= 1 && !c.offline() {
+		failed, err := c.sync(ctx, []forge.HistoryRef{ref})
+		if err == nil {
+			err = failed[refKey(ref)]
+		}
+		if err != nil {
+			return models.CommitPage{}, err
+		}
+	}
+
+	rc, err := c.page(ctx, ref, filters, (max(page, 1)-1)*filters.PerPage)
+	if err != nil {
+		return models.CommitPage{}, err
+	}
+
+	result := models.CommitPage{Commits: rc.Commits, Page: page, TotalCount: rc.TotalCount}
+	if rc.HasMore {
+		result.NextPage = page + 1
+	} else {
+		result.LastPage = page
+	}
+	return result, nil
+}
+
+type syncProgress struct {
+	state   SyncState
+	known   bool
+	found   bool
+	head    string
+	cursor  string
+	hasMore bool
+	total   int
+	commits []models.Commit
+}
+
+func (c *Client) sync(ctx context.Context, refs []forge.HistoryRef) (map[string]error, error) {
+	failed := make(map[string]error)
+	progress := make(map[string]*syncProgress, len(refs))
+	for _, ref := range refs {
+		state, known, err := c.store.SyncState(ctx, ref.Repository.ID(), ref.Branch)
+		if err != nil {
+			return nil, err
+		}
+		progress[refKey(ref)] = &syncProgress{state: state, known: known}
+	}
+
+	pending := refs
+	for page := 0; len(pending) > 0 && page < maxSyncPages; page++ {
+		results, err := c.remote.GetHistories(ctx, pending, syncFilters)
+		if err != nil {
+			return nil, err
+		}
+
+		var next []forge.HistoryRef
+		for i, rc := range results {
+			ref := pending[i]
+			p := progress[refKey(ref)]
+			if rc.Failed() {
+				failed[refKey(ref)] = rc.Err
+				continue
+			}
+
+			if page == 0 && len(rc.Commits) > 0 {
+				p.head = rc.Commits[0].SHA
+				p.total = rc.TotalCount
+			}
+			commits, found := takeUntil(rc.Commits, p.state.Head)
+			p.commits = append(p.commits, commits...)
+			p.cursor = rc.Cursor
+			p.hasMore = rc.HasMore
+			p.found = found && p.known
+
+			if p.found || !p.known || !rc.HasMore {
+				continue
+			}
+			ref.Cursor = rc.Cursor
+			next = append(next, ref)
+		}
+		pending = next
+	}
+
+	for _, ref := range refs {
+		if failed[refKey(ref)] != nil {
+			continue
+		}
+		if err := c.save(ctx, ref, progress[refKey(ref)]); err != nil {
+			return nil, err
+		}
+	}
+	return failed, nil
+}
+
+func (c *Client) save(ctx context.Context, ref forge.HistoryRef, p *syncProgress) error {
+	name := ref.Repository.ID()
+	state := SyncState{Head: p.head, Cursor: p.cursor, Complete: !p.hasMore, SyncedAt: c.now()}
+
+	switch {
+	case p.found:
+		state.Cursor = p.state.Cursor
+		state.Complete = p.state.Complete
+	case p.known && p.head != p.state.Head:
+		if err := c.store.Reset(ctx, name, ref.Branch); err != nil {
+			return err
+		}
+	case p.known:
+		state = p.state
+		state.SyncedAt = c.now()
+	}
+	if p.total > 0 {
+		state.Total = p.total
+	}
+	return c.store.Save(ctx, name, ref.Branch, p.commits, state)
+}
+
+func (c *Client) page(ctx context.Context, ref forge.HistoryRef, filters models.FilterOptions, offset int) (models.RepoCommits, error) {
+	name := ref.Repository.ID()
+	rc := models.RepoCommits{Repository: ref.Repository, Branch: ref.Branch}
+
+	for backfills := 0; ; backfills++ {
+		state, known, err := c.store.SyncState(ctx, name, ref.Branch)
+		if err != nil {
+			return rc, err
+		}
+		if !known && c.offline() {
+			return rc, ErrNotCached
+		}
+
+		commits, err := c.store.Commits(ctx, name, ref.Branch, filters, offset, filters.PerPage+1)
+		if err != nil {
+			return rc, err
+		}
+		missing, err := c.missingHistory(ctx, ref, state, filters)
+		if err != nil {
+			return rc, err
+		}
+
+		backfill := missing && !c.offline()
+		if len(commits) <= filters.PerPage && backfill && backfills < maxBackfillPages {
+			if err := c.backfill(ctx, ref, state); err != nil {
+				return rc, err
+			}
+			continue
+		}
+
+		rc.HasMore = len(commits) > filters.PerPage || backfill
+		rc.Partial = missing && c.offline()
+		rc.Commits = commits[:min(len(commits), filters.PerPage)]
+		rc.Cursor = strconv.Itoa(offset + len(rc.Commits))
+		rc.SyncedAt = state.SyncedAt
+		switch {
+		case !missing:
+			rc.TotalCount, err = c.store.Count(ctx, name, ref.Branch, filters)
+		case !filters.HasHistoryFilter():
+			rc.TotalCount = state.Total
+		}
+		return rc, err
+	}
+}
+
+func (c *Client) missingHistory(ctx context.Context, ref forge.HistoryRef, state SyncState, filters models.FilterOptions) (bool, error) {
+	if state.Complete || filters.DateFrom == "" {
+		return !state.Complete, nil
+	}
+
+	from, err := parseDate(filters.DateFrom)
+	if err != nil {
+		return false, err
+	}
+	oldest, err := c.store.Oldest(ctx, ref.Repository.ID(), ref.Branch)
+	return !oldest.Before(from), err
+}
+
+func (c *Client) backfill(ctx context.Context, ref forge.HistoryRef, state SyncState) error {
+	name := ref.Repository.ID()
+	ref.Cursor = state.Cursor
+	results, err := c.remote.GetHistories(ctx, []forge.HistoryRef{ref}, syncFilters)
+	if err != nil {
+		return err
+	}
+	if len(results) == 0 {
+		return nil
+	}
+	if results[0].Failed() {
+		return results[0].Err
+	}
+
+	state.Cursor = results[0].Cursor
+	state.Complete = !results[0].HasMore
+	return c.store.Save(ctx, name, ref.Branch, results[0].Commits, state)
+}
+
+func refKey(ref forge.HistoryRef) string {
+	return ref.Repository.ID() + "@" + ref.Branch
+}
+
+func takeUntil(commits []models.Commit, sha string) ([]models.Commit, bool) {
+	for i, c := range commits {
+		if c.SHA == sha {
+			return commits[:i], true
+		}
+	}
+	return commits, false
+}
@@ -0,0 +1,354 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/tkozakas/gh-log/internal/forge"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+type fakeRemote struct {
+	repos    []models.Repository
+	history  []models.Commit
+	requests int
+	fetched  int
+}
+
+func (f *fakeRemote) ListRepositories(ctx context.Context) ([]models.Repository, error) {
+	return f.repos, nil
+}
+
+func (f *fakeRemote) GetRepository(ctx context.Context, nameWithOwner string) (models.Repository, error) {
+	for _, repo := range f.repos {
+		if repo.ID() == nameWithOwner {
+			return repo, nil
+		}
+	}
+	return models.Repository{}, forge.ErrNotFound
+}
+
+func (f *fakeRemote) SearchRepositories(ctx context.Context, query string) ([]models.Repository, error) {
+	return f.repos, nil
+}
+
+func (f *fakeRemote) ListBranches(ctx context.Context, repo models.Repository) ([]models.Branch, error) {
+	return nil, nil
+}
+
+func (f *fakeRemote) GetCommits(ctx context.Context, repo models.Repository, branch string, filters models.FilterOptions, page int) (models.CommitPage, error) {
+	return models.CommitPage{}, nil
+}
+
+func (f *fakeRemote) GetCommit(ctx context.Context, repo models.Repository, sha string) (models.Commit, error) {
+	f.requests++
+	return models.Commit{SHA: sha, Parents: []string{"parent"}, Stats: &models.CommitStats{Additions: 3}}, nil
+}
+
+func (f *fakeRemote) Compare(ctx context.Context, repo models.Repository, base, head string) (models.Comparison, error) {
+	f.requests++
+	return models.Comparison{Base: base, Head: head, AheadBy: len(f.history), Commits: f.history}, nil
+}
+
+func (f *fakeRemote) RateLimit() models.RateLimit {
+	return models.RateLimit{}
+}
+
+func (f *fakeRemote) GetHistories(ctx context.Context, refs []forge.HistoryRef, filters models.FilterOptions) ([]models.RepoCommits, error) {
+	f.requests++
+	results := make([]models.RepoCommits, len(refs))
+	for i, ref := range refs {
+		start, _ := strconv.Atoi(ref.Cursor)
+		end := min(start+filters.PerPage, len(f.history))
+		f.fetched += end - start
+		results[i] = models.RepoCommits{
+			Repository: ref.Repository,
+			Branch:     ref.Branch,
+			Commits:    f.history[start:end],
+			HasMore:    end < len(f.history),
+			Cursor:     strconv.Itoa(end),
+			TotalCount: len(f.history),
+		}
+	}
+	return results, nil
+}
+
+func (f *fakeRemote) push(n int) {
+	commits := make([]models.Commit, n)
+	for i := range commits {
+		commits[i] = commitAt(fmt.Sprintf("c%d", len(f.history)+n-i), "alice", "2024-03-01")
+		commits[i].Date = commits[i].Date.AddDate(0, 0, len(f.history)+n-i)
+	}
+	f.history = append(commits, f.history...)
+}
+
+var testRef = forge.HistoryRef{
+	Repository: models.Repository{Name: "api", NameWithOwner: "octo/api"},
+	Branch:     "main",
+}
+
+func TestClientSyncsIncrementally(t *testing.T) {
+	remote := &fakeRemote{}
+	remote.push(30)
+	client := NewClient(remote, openTestStore(t))
+	filters := models.FilterOptions{PerPage: 10}
+
+	results, err := client.GetHistories(context.Background(), []forge.HistoryRef{testRef}, filters)
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+	if got := results[0]; len(got.Commits) != 10 || got.TotalCount != 30 || !got.HasMore {
+		t.Fatalf("first sync = %d commits, total %d, hasMore %v", len(got.Commits), got.TotalCount, got.HasMore)
+	}
+
+	remote.push(5)
+	remote.fetched = 0
+	results, err = client.GetHistories(context.Background(), []forge.HistoryRef{testRef}, filters)
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+	if got := results[0]; got.Commits[0].SHA != "c35" || got.TotalCount != 35 {
+		t.Errorf("after sync head = %s, total %d, want c35 and 35", got.Commits[0].SHA, got.TotalCount)
+	}
+	if remote.requests != 2 {
+		t.Errorf("requests = %d, want 2", remote.requests)
+	}
+}
+
+func TestClientReportsRemoteTotalWhilePartlySynced(t *testing.T) {
+	remote := &fakeRemote{}
+	remote.push(250)
+	client := NewClient(remote, openTestStore(t))
+
+	results, err := client.GetHistories(context.Background(), []forge.HistoryRef{testRef}, models.FilterOptions{PerPage: 10})
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+	if got := results[0]; remote.fetched != 100 || got.TotalCount != 250 {
+		t.Errorf("fetched %d commits, total %d, want 100 fetched and a total of 250", remote.fetched, got.TotalCount)
+	}
+
+	results, err = client.GetHistories(context.Background(), []forge.HistoryRef{testRef}, models.FilterOptions{PerPage: 10, Author: "alice"})
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+	if got := results[0]; got.TotalCount != 0 {
+		t.Errorf("filtered total = %d, want unknown while history is incomplete", got.TotalCount)
+	}
+}
+
+func TestClientPagesLocally(t *testing.T) {
+	remote := &fakeRemote{}
+	remote.push(25)
+	client := NewClient(remote, openTestStore(t))
+	filters := models.FilterOptions{PerPage: 10}
+
+	var shas []string
+	ref := testRef
+	for {
+		results, err := client.GetHistories(context.Background(), []forge.HistoryRef{ref}, filters)
+		if err != nil {
+			t.Fatalf("GetHistories() error: %v", err)
+		}
+		shas = append(shas, commitSHAs(results[0].Commits)...)
+		if !results[0].HasMore {
+			break
+		}
+		ref.Cursor = results[0].Cursor
+	}
+
+	if len(shas) != 25 || shas[0] != "c25" || shas[24] != "c1" {
+		t.Errorf("paged %d commits from %v", len(shas), shas)
+	}
+	if remote.requests != 1 {
+		t.Errorf("requests = %d, want 1", remote.requests)
+	}
+}
+
+func TestClientBackfillsForDateWindow(t *testing.T) {
+	remote := &fakeRemote{}
+	remote.push(250)
+	client := NewClient(remote, openTestStore(t))
+	filters := models.FilterOptions{PerPage: 10, DateFrom: "2024-03-02", DateTo: "2024-03-06"}
+
+	results, err := client.GetHistories(context.Background(), []forge.HistoryRef{testRef}, filters)
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+
+	got := results[0]
+	if shas := commitSHAs(got.Commits); len(shas) != 5 || shas[0] != "c5" || shas[4] != "c1" {
+		t.Errorf("commits = %v, want c5..c1", shas)
+	}
+	if got.HasMore || got.TotalCount != 5 {
+		t.Errorf("hasMore = %v, total = %d, want false and 5", got.HasMore, got.TotalCount)
+	}
+	if remote.requests != 3 {
+		t.Errorf("requests = %d, want 3", remote.requests)
+	}
+}
+
+func TestClientResyncsAfterHistoryRewrite(t *testing.T) {
+	remote := &fakeRemote{}
+	remote.push(5)
+	client := NewClient(remote, openTestStore(t))
+	filters := models.FilterOptions{PerPage: 10}
+
+	client.GetHistories(context.Background(), []forge.HistoryRef{testRef}, filters)
+	remote.history = nil
+	remote.push(3)
+	remote.history[0].SHA = "rewritten"
+
+	results, err := client.GetHistories(context.Background(), []forge.HistoryRef{testRef}, filters)
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+	if shas := commitSHAs(results[0].Commits); len(shas) != 3 || shas[0] != "rewritten" {
+		t.Errorf("commits = %v, want the rewritten history", shas)
+	}
+}
+
+func TestOfflineClientServesCachedData(t *testing.T) {
+	remote := &fakeRemote{repos: []models.Repository{testRef.Repository}}
+	remote.push(150)
+	s := openTestStore(t)
+	online := NewClient(remote, s)
+	filters := models.FilterOptions{PerPage: 60}
+
+	online.ListRepositories(context.Background())
+	online.GetHistories(context.Background(), []forge.HistoryRef{testRef}, filters)
+
+	offline := NewOfflineClient(s)
+	repos, err := offline.ListRepositories(context.Background())
+	if err != nil || len(repos) != 1 {
+		t.Fatalf("ListRepositories() = %v, %v", repos, err)
+	}
+
+	ref := testRef
+	var pages []models.RepoCommits
+	for {
+		results, err := offline.GetHistories(context.Background(), []forge.HistoryRef{ref}, filters)
+		if err != nil {
+			t.Fatalf("GetHistories() error: %v", err)
+		}
+		pages = append(pages, results[0])
+		if !results[0].HasMore {
+			break
+		}
+		ref.Cursor = results[0].Cursor
+	}
+
+	last := pages[len(pages)-1]
+	if len(pages) != 2 || len(last.Commits) != 40 {
+		t.Errorf("offline pages = %d, last has %d commits, want 2 and 40", len(pages), len(last.Commits))
+	}
+	if !last.Partial || last.SyncedAt.IsZero() {
+		t.Errorf("last page partial = %v, syncedAt = %v", last.Partial, last.SyncedAt)
+	}
+	if remote.requests != 1 {
+		t.Errorf("requests = %d, offline client should not fetch", remote.requests)
+	}
+}
+
+func TestOfflineClientReportsUncachedRepos(t *testing.T) {
+	offline := NewOfflineClient(openTestStore(t))
+
+	if _, err := offline.ListRepositories(context.Background()); !errors.Is(err, ErrNotCached) {
+		t.Errorf("ListRepositories() error = %v, want ErrNotCached", err)
+	}
+
+	results, err := offline.GetHistories(context.Background(), []forge.HistoryRef{testRef}, models.FilterOptions{PerPage: 10})
+	if err != nil {
+		t.Fatalf("GetHistories() error: %v", err)
+	}
+	if !errors.Is(results[0].Err, ErrNotCached) {
+		t.Errorf("repo error = %v, want ErrNotCached", results[0].Err)
+	}
+}
+
+func TestTakeUntil(t *testing.T) {
+	commits := []models.Commit{{SHA: "c"}, {SHA: "b"}, {SHA: "a"}}
+
+	got, found := takeUntil(commits, "b")
+	if !found || len(got) != 1 || got[0].SHA != "c" {
+		t.Errorf("takeUntil(b) = %v, %v", got, found)
+	}
+	if got, found := takeUntil(commits, "x"); found || len(got) != 3 {
+		t.Errorf("takeUntil(x) = %v, %v", got, found)
+	}
+}
+
+func TestOfflineClientServesLookedUpRepos(t *testing.T) {
+	upstream := models.Repository{NameWithOwner: "golang/go", DefaultBranchName: "master"}
+	remote := &fakeRemote{repos: []models.Repository{testRef.Repository, upstream}}
+	s := openTestStore(t)
+	online := NewClient(remote, s)
+
+	online.ListRepositories(context.Background())
+	if _, err := online.GetRepository(context.Background(), "golang/go"); err != nil {
+		t.Fatalf("GetRepository() error: %v", err)
+	}
+
+	offline := NewOfflineClient(s)
+	repo, err := offline.GetRepository(context.Background(), "golang/go")
+	if err != nil || repo.DefaultBranchName != "master" {
+		t.Errorf("GetRepository() = %+v, %v", repo, err)
+	}
+	if _, err := offline.GetRepository(context.Background(), "rust-lang/rust"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("GetRepository() error = %v, want ErrNotCached", err)
+	}
+
+	found, err := offline.SearchRepositories(context.Background(), "GO")
+	if err != nil || len(found) != 1 || found[0].NameWithOwner != "golang/go" {
+		t.Errorf("SearchRepositories() = %+v, %v", found, err)
+	}
+}
+
+func TestOfflineClientServesComparedRefs(t *testing.T) {
+	remote := &fakeRemote{}
+	remote.push(3)
+	s := openTestStore(t)
+
+	if _, err := NewClient(remote, s).Compare(context.Background(), testRef.Repository, "main", "release"); err != nil {
+		t.Fatalf("Compare() error: %v", err)
+	}
+
+	offline := NewOfflineClient(s)
+	comparison, err := offline.Compare(context.Background(), testRef.Repository, "main", "release")
+	if err != nil || comparison.AheadBy != 3 || len(comparison.Commits) != 3 {
+		t.Errorf("offline Compare() = %+v, %v", comparison, err)
+	}
+	if _, err := offline.Compare(context.Background(), testRef.Repository, "main", "other"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("Compare() error = %v, want ErrNotCached", err)
+	}
+	if remote.requests != 1 {
+		t.Errorf("requests = %d, offline client should not fetch", remote.requests)
+	}
+}
+
+func TestClientCachesCommitDetail(t *testing.T) {
+	remote := &fakeRemote{}
+	s := openTestStore(t)
+	online := NewClient(remote, s)
+
+	for range 2 {
+		commit, err := online.GetCommit(context.Background(), testRef.Repository, "abc1234")
+		if err != nil || !commit.HasDetail() || commit.Stats.Additions != 3 {
+			t.Fatalf("GetCommit() = %+v, %v", commit, err)
+		}
+	}
+	if remote.requests != 1 {
+		t.Errorf("requests = %d, want the second lookup served from the store", remote.requests)
+	}
+
+	offline := NewOfflineClient(s)
+	if commit, err := offline.GetCommit(context.Background(), testRef.Repository, "abc1234"); err != nil || len(commit.Parents) != 1 {
+		t.Errorf("offline GetCommit() = %+v, %v", commit, err)
+	}
+	if _, err := offline.GetCommit(context.Background(), testRef.Repository, "def5678"); !errors.Is(err, ErrNotCached) {
+		t.Errorf("GetCommit() error = %v, want ErrNotCached", err)
+	}
+}
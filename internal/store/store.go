@@ -0,0 +1,264 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/tkozakas/gh-log/internal/cache"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS commits (
+	repo    TEXT NOT NULL,
+	branch  TEXT NOT NULL,
+	sha     TEXT NOT NULL,
+	message TEXT NOT NULL,
+	author  TEXT NOT NULL,
+	email   TEXT NOT NULL,
+	login   TEXT NOT NULL,
+	date    INTEGER NOT NULL,
+	url     TEXT NOT NULL,
+	PRIMARY KEY (repo, branch, sha)
+);
+CREATE INDEX IF NOT EXISTS commits_by_date ON commits (repo, branch, date DESC);
+CREATE TABLE IF NOT EXISTS sync_state (
+	repo      TEXT NOT NULL,
+	branch    TEXT NOT NULL,
+	head      TEXT NOT NULL,
+	cursor    TEXT NOT NULL,
+	complete  INTEGER NOT NULL,
+	synced_at INTEGER NOT NULL,
+	total     INTEGER NOT NULL,
+	PRIMARY KEY (repo, branch)
+);
+CREATE TABLE IF NOT EXISTS snapshots (
+	key        TEXT PRIMARY KEY,
+	data       BLOB NOT NULL,
+	fetched_at INTEGER NOT NULL
+);`
+
+var ErrNotCached = errors.New("not in local cache")
+
+type Store struct {
+	db *sql.DB
+}
+
+type SyncState struct {
+	Head     string
+	Cursor   string
+	Complete bool
+	SyncedAt time.Time
+	Total    int
+}
+
+func DefaultPath() (string, error) {
+	dir, err := cache.DefaultDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "commits.db"), nil
+}
+
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("init commit store: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) SyncState(ctx context.Context, repo, branch string) (SyncState, bool, error) {
+	var state SyncState
+	var syncedAt int64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT head, cursor, complete, synced_at, total FROM sync_state WHERE repo = ? AND branch = ?`,
+		repo, branch).Scan(&state.Head, &state.Cursor, &state.Complete, &syncedAt, &state.Total)
+	if err == sql.ErrNoRows {
+		return SyncState{}, false, nil
+	}
+	if err != nil {
+		return SyncState{}, false, err
+	}
+	state.SyncedAt = time.Unix(syncedAt, 0)
+	return state, true, nil
+}
+
+func (s *Store) Save(ctx context.Context, repo, branch string, commits []models.Commit, state SyncState) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, c := range commits {
+		_, err := tx.ExecContext(ctx,
+			`INSERT OR IGNORE INTO commits (repo, branch, sha, message, author, email, login, date, url) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			repo, branch, c.SHA, c.Message, c.Author, c.Email, c.Login, c.Date.Unix(), c.URL)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO sync_state (repo, branch, head, cursor, complete, synced_at, total) VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (repo, branch) DO UPDATE SET head = excluded.head, cursor = excluded.cursor, complete = excluded.complete, synced_at = excluded.synced_at, total = excluded.total`,
+		repo, branch, state.Head, state.Cursor, state.Complete, state.SyncedAt.Unix(), state.Total)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) Reset(ctx context.Context, repo, branch string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM commits WHERE repo = ? AND branch = ?`, repo, branch); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM sync_state WHERE repo = ? AND branch = ?`, repo, branch); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *Store) PutSnapshot(ctx context.Context, key string, v any, fetchedAt time.Time) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(ctx,
+		`INSERT INTO snapshots (key, data, fetched_at) VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET data = excluded.data, fetched_at = excluded.fetched_at`,
+		key, data, fetchedAt.Unix())
+	return err
+}
+
+func (s *Store) Snapshot(ctx context.Context, key string, v any) (time.Time, error) {
+	var data []byte
+	var fetchedAt int64
+	err := s.db.QueryRowContext(ctx, `SELECT data, fetched_at FROM snapshots WHERE key = ?`, key).Scan(&data, &fetchedAt)
+	if err == sql.ErrNoRows {
+		return time.Time{}, ErrNotCached
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(fetchedAt, 0), json.Unmarshal(data, v)
+}
+
+func (s *Store) RepositoriesFetchedAt(ctx context.Context) (time.Time, error) {
+	var repos []models.Repository
+	return s.Snapshot(ctx, repositoriesKey, &repos)
+}
+
+func (s *Store) Commits(ctx context.Context, repo, branch string, filters models.FilterOptions, offset, limit int) ([]models.Commit, error) {
+	where, args, err := filterClause(repo, branch, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT sha, message, author, email, login, date, url FROM commits WHERE `+where+` ORDER BY date DESC, sha LIMIT ? OFFSET ?`,
+		append(args, limit, offset)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var commits []models.Commit
+	for rows.Next() {
+		var c models.Commit
+		var date int64
+		if err := rows.Scan(&c.SHA, &c.Message, &c.Author, &c.Email, &c.Login, &date, &c.URL); err != nil {
+			return nil, err
+		}
+		c.Date = time.Unix(date, 0).UTC()
+		commits = append(commits, c)
+	}
+	return commits, rows.Err()
+}
+
+func (s *Store) Count(ctx context.Context, repo, branch string, filters models.FilterOptions) (int, error) {
+	where, args, err := filterClause(repo, branch, filters)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM commits WHERE `+where, args...).Scan(&count)
+	return count, err
+}
+
+func (s *Store) Oldest(ctx context.Context, repo, branch string) (time.Time, error) {
+	var date sql.NullInt64
+	err := s.db.QueryRowContext(ctx,
+		`SELECT MIN(date) FROM commits WHERE repo = ? AND branch = ?`, repo, branch).Scan(&date)
+	if err != nil || !date.Valid {
+		return time.Time{}, err
+	}
+	return time.Unix(date.Int64, 0).UTC(), nil
+}
+
+func filterClause(repo, branch string, filters models.FilterOptions) (string, []any, error) {
+	conditions := []string{"repo = ?", "branch = ?"}
+	args := []any{repo, branch}
+
+	if filters.DateFrom != "" {
+		from, err := parseDate(filters.DateFrom)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, "date >= ?")
+		args = append(args, from.Unix())
+	}
+	if filters.DateTo != "" {
+		to, err := parseDate(filters.DateTo)
+		if err != nil {
+			return "", nil, err
+		}
+		conditions = append(conditions, "date < ?")
+		args = append(args, to.AddDate(0, 0, 1).Unix())
+	}
+	if filters.Author != "" {
+		pattern := "%" + filters.Author + "%"
+		conditions = append(conditions, "(login = ? COLLATE NOCASE OR email = ? COLLATE NOCASE OR (login = '' AND (author LIKE ? OR email LIKE ?)))")
+		args = append(args, filters.Author, filters.Author, pattern, pattern)
+	}
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+func parseDate(date string) (time.Time, error) {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, want YYYY-MM-DD", date)
+	}
+	return t, nil
+}
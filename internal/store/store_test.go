@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "commits.db"))
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func commitAt(sha, author, date string) models.Commit {
+	d, _ := time.Parse(time.DateOnly, date)
+	return models.Commit{SHA: sha, Author: author, Email: author + "@example.com", Date: d.Add(12 * time.Hour)}
+}
+
+func TestStoreCommitsFilters(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+	linked := commitAt("c4", "Mona Lisa", "2024-03-12")
+	linked.Email, linked.Login = "mona@example.com", "octocat"
+	commits := []models.Commit{
+		linked,
+		commitAt("c3", "alice", "2024-03-10"),
+		commitAt("c2", "bob", "2024-03-05"),
+		commitAt("c1", "alice", "2024-03-01"),
+	}
+	if err := s.Save(ctx, "octo/api", "main", commits, SyncState{Head: "c3"}); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		filters models.FilterOptions
+		want    []string
+	}{
+		{"all", models.FilterOptions{}, []string{"c4", "c3", "c2", "c1"}},
+		{"author", models.FilterOptions{Author: "ALICE"}, []string{"c3", "c1"}},
+		{"email", models.FilterOptions{Author: "bob@example.com"}, []string{"c2"}},
+		{"login", models.FilterOptions{Author: "OctoCat"}, []string{"c4"}},
+		{"linked email", models.FilterOptions{Author: "MONA@example.com"}, []string{"c4"}},
+		{"linked name", models.FilterOptions{Author: "Mona"}, nil},
+		{"from", models.FilterOptions{DateFrom: "2024-03-05"}, []string{"c4", "c3", "c2"}},
+		{"to inclusive", models.FilterOptions{DateTo: "2024-03-05"}, []string{"c2", "c1"}},
+		{"combined", models.FilterOptions{DateFrom: "2024-03-02", Author: "alice"}, []string{"c3"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.Commits(ctx, "octo/api", "main", tt.filters, 0, 10)
+			if err != nil {
+				t.Fatalf("Commits() error: %v", err)
+			}
+			if shas := commitSHAs(got); !slices.Equal(shas, tt.want) {
+				t.Errorf("Commits() = %v, want %v", shas, tt.want)
+			}
+			count, err := s.Count(ctx, "octo/api", "main", tt.filters)
+			if err != nil || count != len(tt.want) {
+				t.Errorf("Count() = %d, %v, want %d", count, err, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestStoreSaveIgnoresDuplicates(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+	c := commitAt("c1", "alice", "2024-03-01")
+
+	for range 2 {
+		if err := s.Save(ctx, "octo/api", "main", []models.Commit{c}, SyncState{Head: "c1"}); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+
+	if count, _ := s.Count(ctx, "octo/api", "main", models.FilterOptions{}); count != 1 {
+		t.Errorf("Count() = %d, want 1", count)
+	}
+}
+
+func TestStoreSyncStateAndReset(t *testing.T) {
+	s := openTestStore(t)
+	ctx := context.Background()
+
+	if _, ok, err := s.SyncState(ctx, "octo/api", "main"); ok || err != nil {
+		t.Fatalf("SyncState() = %v, %v, want miss", ok, err)
+	}
+
+	want := SyncState{Head: "c1", Cursor: "abc", Complete: true, SyncedAt: time.Unix(1700000000, 0)}
+	s.Save(ctx, "octo/api", "main", []models.Commit{commitAt("c1", "alice", "2024-03-01")}, want)
+
+	got, ok, err := s.SyncState(ctx, "octo/api", "main")
+	if !ok || err != nil || got != want {
+		t.Errorf("SyncState() = %+v, %v, %v, want %+v", got, ok, err, want)
+	}
+
+	if err := s.Reset(ctx, "octo/api", "main"); err != nil {
+		t.Fatalf("Reset() error: %v", err)
+	}
+	if _, ok, _ := s.SyncState(ctx, "octo/api", "main"); ok {
+		t.Error("SyncState() should miss after Reset")
+	}
+	if count, _ := s.Count(ctx, "octo/api", "main", models.FilterOptions{}); count != 0 {
+		t.Errorf("Count() = %d after Reset, want 0", count)
+	}
+}
+
+func TestStoreRejectsInvalidDate(t *testing.T) {
+	s := openTestStore(t)
+
+	if _, err := s.Commits(context.Background(), "octo/api", "main", models.FilterOptions{DateFrom: "yesterday"}, 0, 10); err == nil {
+		t.Error("expected error for invalid date")
+	}
+}
+
+func commitSHAs(commits []models.Commit) []string {
+	shas := make([]string, len(commits))
+	for i, c := range commits {
+		shas[i] = c.SHA
+	}
+	return shas
+}
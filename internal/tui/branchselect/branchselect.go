@@ -14,13 +14,18 @@ import (
 type item struct {
 	name      string
 	isDefault bool
+	picked    map[string]bool
 }
 
 func (i item) Title() string {
+	title := i.name
 	if i.isDefault {
-		return i.name + " (default)"
+		title += " (default)"
 	}
-	return i.name
+	if i.picked[i.name] {
+		title = "✓ " + title
+	}
+	return title
 }
 
 func (i item) Description() string { return "" }
@@ -30,21 +35,25 @@ type Model struct {
 	list           list.Model
 	repo           models.Repository
 	selectedBranch string
+	picked         map[string]bool
 }
 
 type DoneMsg struct {
-	Repo   models.Repository
-	Branch string
+	Repo     models.Repository
+	Branches []string
 }
 
 type UseDefaultMsg struct{}
 
-func New(repo models.Repository, branches []string, width, height int) Model {
+func New(repo models.Repository, branches []models.Branch, width, height int) Model {
 	defaultBranch := repo.DefaultBranchName
-	items := make([]list.Item, len(branches))
+	sorted := append([]models.Branch(nil), branches...)
+	models.SortBranchesByRecency(sorted)
 
-	for i, b := range branches {
-		items[i] = item{name: b, isDefault: b == defaultBranch}
+	picked := make(map[string]bool)
+	items := make([]list.Item, len(sorted))
+	for i, b := range sorted {
+		items[i] = item{name: b.Name, isDefault: b.Name == defaultBranch, picked: picked}
 	}
 
 	delegate := list.NewDefaultDelegate()
@@ -54,15 +63,16 @@ func New(repo models.Repository, branches []string, width, height int) Model {
 		BorderLeftForeground(tui.ColorPrimary)
 
 	l := list.New(items, delegate, width, height-4)
-	l.Title = fmt.Sprintf("Select branch for %s", repo.NameWithOwner)
+	l.Title = fmt.Sprintf("Select branch for %s", repo.ID())
 	l.SetShowStatusBar(false)
 	l.SetFilteringEnabled(true)
 	l.Styles.Title = tui.TitleStyle
 	l.AdditionalShortHelpKeys = shortHelpKeys
 
 	return Model{
-		list: l,
-		repo: repo,
+		list:   l,
+		repo:   repo,
+		picked: picked,
 	}
 }
 
@@ -82,6 +92,15 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		}
 
 		switch {
+		case key.Matches(msg, tui.Keys.Select):
+			if selected, ok := m.list.SelectedItem().(item); ok {
+				if m.picked[selected.name] {
+					delete(m.picked, selected.name)
+				} else {
+					m.picked[selected.name] = true
+				}
+			}
+			return m, nil
 		case key.Matches(msg, tui.Keys.Confirm):
 			if len(m.list.Items()) > 0 {
 				selected := m.list.SelectedItem().(item)
@@ -91,6 +110,7 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		case key.Matches(msg, tui.Keys.Default):
 			return m, m.submitDefault
 		case key.Matches(msg, tui.Keys.Back):
+			clear(m.picked)
 			m.selectedBranch = m.repo.DefaultBranchName
 			return m, m.submit
 		case key.Matches(msg, tui.Keys.Quit):
@@ -104,16 +124,26 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 }
 
 func (m Model) View() string {
-	help := tui.HelpStyle.Render("  d: use default for all remaining")
+	help := tui.HelpStyle.Render("  space: add branch • enter: confirm • d: use default for all remaining")
 	return m.list.View() + "\n" + help
 }
 
 func (m Model) submit() tea.Msg {
+	var branches []string
+	for _, it := range m.list.Items() {
+		if name := it.(item).name; m.picked[name] {
+			branches = append(branches, name)
+		}
+	}
+	if len(branches) > 0 {
+		return DoneMsg{Repo: m.repo, Branches: branches}
+	}
+
 	branch := m.selectedBranch
 	if branch == "" {
 		branch = m.repo.DefaultBranchName
 	}
-	return DoneMsg{Repo: m.repo, Branch: branch}
+	return DoneMsg{Repo: m.repo, Branches: []string{branch}}
 }
 
 func (m Model) submitDefault() tea.Msg {
@@ -121,5 +151,5 @@ func (m Model) submitDefault() tea.Msg {
 }
 
 func shortHelpKeys() []key.Binding {
-	return []key.Binding{tui.Keys.Default}
+	return []key.Binding{tui.Keys.Select, tui.Keys.Default}
 }
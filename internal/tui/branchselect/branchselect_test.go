@@ -2,6 +2,9 @@ package branchselect
 
 import (
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/tkozakas/gh-log/internal/models"
 )
@@ -15,6 +18,7 @@ func TestItemTitle(t *testing.T) {
 		{"regular", item{name: "main", isDefault: false}, "main"},
 		{"default", item{name: "main", isDefault: true}, "main (default)"},
 		{"featureBranch", item{name: "feature/test", isDefault: false}, "feature/test"},
+		{"picked", item{name: "main", isDefault: true, picked: map[string]bool{"main": true}}, "✓ main (default)"},
 	}
 
 	for _, tt := range tests {
@@ -59,13 +63,38 @@ func TestModelSubmit(t *testing.T) {
 				selectedBranch: tt.selectedBranch,
 			}
 			msg := m.submit().(DoneMsg)
-			if msg.Branch != tt.expected {
-				t.Errorf("Branch = %q, want %q", msg.Branch, tt.expected)
+			if len(msg.Branches) != 1 || msg.Branches[0] != tt.expected {
+				t.Errorf("Branches = %v, want [%s]", msg.Branches, tt.expected)
 			}
 		})
 	}
 }
 
+func TestModelSubmitPicked(t *testing.T) {
+	now := time.Now()
+	repo := models.Repository{NameWithOwner: "owner/repo", DefaultBranchName: "main"}
+	branches := []models.Branch{
+		{Name: "main", CommittedAt: now.Add(-time.Hour)},
+		{Name: "release/1.2", CommittedAt: now},
+		{Name: "dev", CommittedAt: now.Add(-2 * time.Hour)},
+	}
+	m := New(repo, branches, 80, 24)
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+
+	m, _ = m.Update(space)
+	m.list.Select(1)
+	m, _ = m.Update(space)
+	m.list.Select(2)
+	m, _ = m.Update(space)
+	m, _ = m.Update(space)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg := cmd().(DoneMsg)
+	if len(msg.Branches) != 2 || msg.Branches[0] != "release/1.2" || msg.Branches[1] != "main" {
+		t.Errorf("Branches = %v, want [release/1.2 main]", msg.Branches)
+	}
+}
+
 func TestModelSubmitDefault(t *testing.T) {
 	m := Model{}
 	msg := m.submitDefault()
@@ -73,3 +102,22 @@ func TestModelSubmitDefault(t *testing.T) {
 		t.Errorf("submitDefault() returned %T, want UseDefaultMsg", msg)
 	}
 }
+
+func TestNewSortsByRecency(t *testing.T) {
+	now := time.Now()
+	repo := models.Repository{NameWithOwner: "owner/repo", DefaultBranchName: "main"}
+	branches := []models.Branch{
+		{Name: "main", CommittedAt: now.Add(-24 * time.Hour)},
+		{Name: "feature", CommittedAt: now},
+	}
+
+	m := New(repo, branches, 80, 24)
+
+	items := m.list.Items()
+	if got := items[0].(item).name; got != "feature" {
+		t.Errorf("first item = %q, want %q", got, "feature")
+	}
+	if !items[1].(item).isDefault {
+		t.Error("expected main to be marked as default")
+	}
+}
@@ -2,9 +2,11 @@ package commitview
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 
@@ -16,29 +18,83 @@ type Model struct {
 	viewport     viewport.Model
 	repoCommits  []models.RepoCommits
 	expanded     map[int]bool
+	details      map[string]detailState
 	cursor       int
 	totalCommits int
+	rateLimit    models.RateLimit
+	offline      bool
+	input        textinput.Model
+	prompt       promptKind
+	compareRepo  models.Repository
+	comparison   *models.Comparison
+	standalone   bool
+	status       string
 	width        int
 	height       int
 	ready        bool
 	loading      bool
 }
 
+type promptKind int
+
+type detailState struct {
+	loading bool
+	err     error
+}
+
+const (
+	promptNone promptKind = iota
+	promptWorkspace
+	promptCompare
+)
+
+const (
+	maxBadges = 3
+	maxFiles  = 20
+)
+
 type RestartMsg struct{}
 
+type BackMsg struct{}
+
+type RetryMsg struct {
+	RepoNames []string
+}
+
+type SaveWorkspaceMsg struct {
+	Name string
+}
+
+type CompareMsg struct {
+	Repo models.Repository
+	Base string
+	Head string
+}
+
+type LoadDetailMsg struct {
+	Repo models.Repository
+	SHA  string
+}
+
 type LoadMoreMsg struct {
 	RepoName string
 	NextPage int
+	Cursor   string
 }
 
 func New(repoCommits []models.RepoCommits, width, height int) Model {
 	vp := viewport.New(width, height-4)
 	vp.Style = tui.BoxStyle
 
+	input := textinput.New()
+	input.Width = 30
+
 	m := Model{
 		viewport:    vp,
+		input:       input,
 		repoCommits: repoCommits,
 		expanded:    make(map[int]bool),
+		details:     make(map[string]detailState),
 		width:       width,
 		height:      height,
 	}
@@ -49,13 +105,57 @@ func New(repoCommits []models.RepoCommits, width, height int) Model {
 	return m
 }
 
+func NewComparison(repo models.Repository, comparison models.Comparison, width, height int) Model {
+	rc := models.RepoCommits{
+		Repository: repo,
+		Branch:     comparison.Spec(),
+		Commits:    comparison.Commits,
+		TotalCount: comparison.AheadBy,
+	}
+	m := New([]models.RepoCommits{rc}, width, height)
+	m.comparison = &comparison
+	m.updateContent()
+	return m
+}
+
 func (m *Model) UpdateCommits(repoCommits []models.RepoCommits) {
+	keys := m.commitKeys()
 	m.repoCommits = repoCommits
 	m.totalCommits = m.countCommits()
+	m.reanchor(keys)
 	m.loading = false
 	m.updateContent()
 }
 
+func (m *Model) SetDetail(repoID, sha string, detail models.Commit, err error) {
+	key := repoID + "@" + sha
+	if err != nil {
+		m.details[key] = detailState{err: err}
+	} else {
+		delete(m.details, key)
+		m.repoCommits = models.SetCommitDetail(m.repoCommits, repoID, detail)
+	}
+	m.updateContent()
+}
+
+func (m *Model) SetRateLimit(limit models.RateLimit) {
+	m.rateLimit = limit
+}
+
+func (m *Model) SetOffline(offline bool) {
+	m.offline = offline
+	m.updateContent()
+}
+
+func (m *Model) SetStandalone(standalone bool) {
+	m.standalone = standalone
+	m.updateContent()
+}
+
+func (m *Model) SetStatus(status string) {
+	m.status = status
+}
+
 func (m Model) Init() tea.Cmd {
 	return nil
 }
@@ -71,15 +171,32 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		return m, nil
 
 	case tea.KeyMsg:
+		if m.prompt != promptNone {
+			return m.updateInput(msg)
+		}
 		switch {
 		case key.Matches(msg, tui.Keys.Quit):
 			return m, tea.Quit
+		case key.Matches(msg, tui.Keys.Back) && m.comparison != nil && !m.standalone:
+			return m, func() tea.Msg { return BackMsg{} }
+		case key.Matches(msg, tui.Keys.Save) && m.comparison == nil:
+			return m, m.openPrompt(promptWorkspace, "Save workspace as: ", "payments")
+		case key.Matches(msg, tui.Keys.Compare) && m.comparison == nil:
+			repo, ok := m.currentRepo()
+			if !ok {
+				return m, nil
+			}
+			m.compareRepo = repo
+			return m, m.openPrompt(promptCompare, "Compare "+repo.ID()+": ", repo.DefaultBranchName+"...head")
 		case key.Matches(msg, tui.Keys.Restart):
 			return m, func() tea.Msg { return RestartMsg{} }
+		case key.Matches(msg, tui.Keys.Retry):
+			return m, m.retryFailed()
 		case key.Matches(msg, tui.Keys.Confirm):
 			m.toggleExpanded()
+			cmd := m.requestDetail()
 			m.updateContent()
-			return m, nil
+			return m, cmd
 		case key.Matches(msg, tui.Keys.Up):
 			m.moveCursor(-1)
 			m.updateContent()
@@ -107,20 +224,99 @@ func (m Model) View() string {
 	}
 
 	title := tui.TitleStyle.Render("Commits")
-	help := tui.HelpStyle.Render("↑/↓: navigate • enter: expand • n: load more • r: restart • q: quit")
+	helpText := "↑/↓: navigate • enter: expand • n: load more • c: compare • w: save workspace • r: restart • q: quit"
+	if len(m.failedRepos()) > 0 {
+		helpText = "↑/↓: navigate • enter: expand • n: load more • t: retry failed • c: compare • w: save workspace • r: restart • q: quit"
+	}
+	if m.comparison != nil {
+		title = tui.TitleStyle.Render("Compare")
+		helpText = "↑/↓: navigate • enter: expand • esc: back • r: restart • q: quit"
+		if m.standalone {
+			helpText = "↑/↓: navigate • enter: expand • r: restart • q: quit"
+		}
+	}
+	help := tui.HelpStyle.Render(helpText)
+	if quota := m.renderRateLimit(); quota != "" {
+		help += tui.HelpStyle.Render(" • ") + quota
+	}
+	if m.offline {
+		help += tui.HelpStyle.Render(" • ") + tui.HelpStyle.Foreground(tui.ColorWarning).Render("offline")
+	}
+	if m.prompt != promptNone {
+		help += "\n  " + m.input.View()
+	} else if m.status != "" {
+		help += "\n  " + tui.SelectedStyle.Render(m.status)
+	}
 
 	return fmt.Sprintf("%s\n%s\n%s", title, m.viewport.View(), help)
 }
 
+func (m Model) updateInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, tui.Keys.Back):
+		m.prompt = promptNone
+		m.input.Blur()
+		m.input.Reset()
+		return m, nil
+	case key.Matches(msg, tui.Keys.Confirm):
+		value := strings.TrimSpace(m.input.Value())
+		if value == "" {
+			return m, nil
+		}
+		kind := m.prompt
+		m.prompt = promptNone
+		m.input.Blur()
+		m.input.Reset()
+		if kind == promptCompare {
+			return m.submitCompare(value)
+		}
+		return m, func() tea.Msg { return SaveWorkspaceMsg{Name: value} }
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) openPrompt(kind promptKind, prompt, placeholder string) tea.Cmd {
+	m.prompt = kind
+	m.status = ""
+	m.input.Prompt = prompt
+	m.input.Placeholder = placeholder
+	return m.input.Focus()
+}
+
+func (m Model) submitCompare(spec string) (Model, tea.Cmd) {
+	base, head, err := models.ParseCompareSpec(spec, m.compareRepo.DefaultBranchName)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	repo := m.compareRepo
+	m.status = fmt.Sprintf("comparing %s...%s", base, head)
+	return m, func() tea.Msg { return CompareMsg{Repo: repo, Base: base, Head: head} }
+}
+
 func (m *Model) updateContent() {
 	var content strings.Builder
 	commitIndex := 0
 	cursorLine := 0
 	lineCount := 0
 
+	if m.comparison != nil {
+		summary := m.renderComparison()
+		content.WriteString(summary)
+		content.WriteString("\n")
+		lineCount += strings.Count(summary, "\n") + 1
+	}
+
 	for _, rc := range m.repoCommits {
-		header := fmt.Sprintf("═══ %s (%s) - %d commits ═══",
-			rc.Repository.NameWithOwner, rc.Branch, len(rc.Commits))
+		label := commitCountLabel(rc)
+		if m.offline && !rc.SyncedAt.IsZero() {
+			label += ", synced " + models.Age(rc.SyncedAt)
+		}
+		header := fmt.Sprintf("═══ %s (%s) - %s ═══",
+			rc.Repository.ID(), rc.BranchLabel(), label)
 		content.WriteString(tui.RepoHeaderStyle.Render(header))
 		content.WriteString("\n\n")
 		lineCount += 2
@@ -129,17 +325,37 @@ func (m *Model) updateContent() {
 			if commitIndex == m.cursor {
 				cursorLine = lineCount
 			}
-			commitStr := m.renderCommit(c, commitIndex)
+			commitStr := m.renderCommit(rc, c, commitIndex)
 			content.WriteString(commitStr)
 			content.WriteString("\n")
 			lineCount += strings.Count(commitStr, "\n") + 1
 			commitIndex++
 		}
 
-		if rc.HasMore {
+		if rc.Status == models.RepoStatusLoading {
+			content.WriteString(tui.DimStyle.Render("    loading..."))
+			content.WriteString("\n")
+			lineCount += 1
+		}
+
+		if rc.Failed() {
+			content.WriteString(tui.ErrorStyle.Render(fmt.Sprintf("    ✗ %v", rc.Err)))
+			content.WriteString("\n")
+			content.WriteString(tui.DimStyle.Render("    press 't' to retry"))
+			content.WriteString("\n")
+			lineCount += 2
+		} else if rc.Status == models.RepoStatusLoaded && len(rc.Commits) == 0 {
+			content.WriteString(tui.DimStyle.Render("    no commits"))
+			content.WriteString("\n")
+			lineCount += 1
+		} else if rc.HasMore {
 			content.WriteString(tui.DimStyle.Render("    ↓ press 'n' to load more..."))
 			content.WriteString("\n")
 			lineCount += 1
+		} else if rc.Partial {
+			content.WriteString(tui.WarningStyle.Render("    older commits are not cached (offline)"))
+			content.WriteString("\n")
+			lineCount += 1
 		}
 		content.WriteString("\n")
 		lineCount += 1
@@ -154,7 +370,7 @@ func (m *Model) updateContent() {
 	m.ensureCursorVisible(cursorLine)
 }
 
-func (m Model) renderCommit(c models.Commit, index int) string {
+func (m Model) renderCommit(rc models.RepoCommits, c models.Commit, index int) string {
 	cursor := "  "
 	if index == m.cursor {
 		cursor = "> "
@@ -165,9 +381,15 @@ func (m Model) renderCommit(c models.Commit, index int) string {
 	author := tui.CommitAuthorStyle.Render(c.Author)
 
 	header := fmt.Sprintf("%s%s │ %s │ %s", cursor, sha, date, author)
+	if badges := renderBranchBadges(c.Branches); badges != "" {
+		header += " " + badges
+	}
+	if rc.IsUnmerged(c) {
+		header += " " + tui.WarningStyle.Render("not on "+rc.Repository.DefaultBranchName)
+	}
 
 	if m.expanded[index] {
-		return header + "\n" + m.renderExpandedMessage(c)
+		return header + "\n" + m.renderExpandedMessage(rc, c)
 	}
 
 	message := c.FirstLine()
@@ -178,7 +400,60 @@ func (m Model) renderCommit(c models.Commit, index int) string {
 	return header + "\n     └─ " + message
 }
 
-func (m Model) renderExpandedMessage(c models.Commit) string {
+func renderBranchBadges(branches []string) string {
+	var badges []string
+	for _, branch := range branches[:min(len(branches), maxBadges)] {
+		badges = append(badges, tui.BranchBadgeStyle.Render(branch))
+	}
+	if extra := len(branches) - maxBadges; extra > 0 {
+		badges = append(badges, tui.DimStyle.Render(fmt.Sprintf("+%d", extra)))
+	}
+	return strings.Join(badges, " ")
+}
+
+func (m Model) renderComparison() string {
+	c := m.comparison
+	var b strings.Builder
+	b.WriteString(tui.SelectedStyle.Render(fmt.Sprintf("  %s • %s", c.Spec(), c.Status)))
+	b.WriteString("\n  " + c.Summary() + "\n")
+	for _, line := range renderFiles(c.Files, maxFiles) {
+		b.WriteString("    " + line + "\n")
+	}
+	return b.String()
+}
+
+func renderFiles(files []models.FileChange, limit int) []string {
+	var lines []string
+	for _, f := range files[:min(len(files), limit)] {
+		line := fileStatusLetter(f.Status) + " " + f.Path()
+		if f.Additions > 0 || f.Deletions > 0 {
+			line += " " + tui.SuccessStyle.Render(fmt.Sprintf("+%d", f.Additions)) +
+				" " + tui.ErrorStyle.Render(fmt.Sprintf("-%d", f.Deletions))
+		}
+		lines = append(lines, line)
+	}
+	if extra := len(files) - limit; extra > 0 {
+		lines = append(lines, tui.DimStyle.Render(fmt.Sprintf("… %d more files", extra)))
+	}
+	return lines
+}
+
+func fileStatusLetter(status string) string {
+	switch status {
+	case "added":
+		return tui.SuccessStyle.Render("A")
+	case "removed":
+		return tui.ErrorStyle.Render("D")
+	case "renamed":
+		return tui.WarningStyle.Render("R")
+	case "copied":
+		return "C"
+	default:
+		return "M"
+	}
+}
+
+func (m Model) renderExpandedMessage(rc models.RepoCommits, c models.Commit) string {
 	var lines strings.Builder
 	lines.WriteString("   ┌─────────────────────────────────────\n")
 	lines.WriteString(fmt.Sprintf("   │ SHA:    %s\n", c.SHA))
@@ -189,11 +464,62 @@ func (m Model) renderExpandedMessage(c models.Commit) string {
 	for _, line := range strings.Split(c.Message, "\n") {
 		lines.WriteString(fmt.Sprintf("   │ %s\n", line))
 	}
+	for _, line := range m.renderDetail(rc, c) {
+		lines.WriteString(fmt.Sprintf("   │ %s\n", line))
+	}
 
 	lines.WriteString("   └─────────────────────────────────────")
 	return lines.String()
 }
 
+func (m Model) renderDetail(rc models.RepoCommits, c models.Commit) []string {
+	state := m.details[commitKey(rc, c)]
+	switch {
+	case state.err != nil:
+		return []string{"", tui.ErrorStyle.Render(fmt.Sprintf("could not load changes: %v", state.err)) + tui.DimStyle.Render(" • enter twice to retry")}
+	case !c.HasDetail():
+		return []string{"", tui.DimStyle.Render("loading changes...")}
+	}
+
+	lines := []string{""}
+	if len(c.Parents) > 0 {
+		parents := make([]string, len(c.Parents))
+		for i, p := range c.Parents {
+			parents[i] = models.Commit{SHA: p}.ShortSHA()
+		}
+		lines = append(lines, "Parents: "+tui.CommitSHAStyle.Render(strings.Join(parents, ", ")))
+	}
+	lines = append(lines, tui.DimStyle.Render(models.ChangeSummary(len(c.Files), c.Stats.Additions, c.Stats.Deletions)))
+	for _, line := range renderFiles(c.Files, maxFiles) {
+		lines = append(lines, "  "+line)
+	}
+	return lines
+}
+
+func (m *Model) requestDetail() tea.Cmd {
+	if !m.expanded[m.cursor] {
+		return nil
+	}
+	rc, c, ok := m.commitAt(m.cursor)
+	if !ok || c.HasDetail() || m.details[commitKey(rc, c)].loading {
+		return nil
+	}
+
+	m.details[commitKey(rc, c)] = detailState{loading: true}
+	repo, sha := rc.Repository, c.SHA
+	return func() tea.Msg { return LoadDetailMsg{Repo: repo, SHA: sha} }
+}
+
+func (m Model) commitAt(index int) (models.RepoCommits, models.Commit, bool) {
+	for _, rc := range m.repoCommits {
+		if index < len(rc.Commits) {
+			return rc, rc.Commits[index], true
+		}
+		index -= len(rc.Commits)
+	}
+	return models.RepoCommits{}, models.Commit{}, false
+}
+
 func (m *Model) toggleExpanded() {
 	m.expanded[m.cursor] = !m.expanded[m.cursor]
 }
@@ -223,6 +549,45 @@ func (m *Model) ensureCursorVisible(cursorLine int) {
 	}
 }
 
+func (m Model) commitKeys() []string {
+	keys := make([]string, 0, m.totalCommits)
+	for _, rc := range m.repoCommits {
+		for _, c := range rc.Commits {
+			keys = append(keys, commitKey(rc, c))
+		}
+	}
+	return keys
+}
+
+func (m *Model) reanchor(previous []string) {
+	indexes := make(map[string]int, m.totalCommits)
+	for i, k := range m.commitKeys() {
+		indexes[k] = i
+	}
+
+	expanded := make(map[int]bool, len(m.expanded))
+	for i, isExpanded := range m.expanded {
+		if i >= len(previous) || !isExpanded {
+			continue
+		}
+		if idx, ok := indexes[previous[i]]; ok {
+			expanded[idx] = true
+		}
+	}
+	m.expanded = expanded
+
+	if m.cursor < len(previous) {
+		if idx, ok := indexes[previous[m.cursor]]; ok {
+			m.cursor = idx
+		}
+	}
+	m.moveCursor(0)
+}
+
+func commitKey(rc models.RepoCommits, c models.Commit) string {
+	return rc.Repository.ID() + "@" + c.SHA
+}
+
 func (m Model) countCommits() int {
 	count := 0
 	for _, rc := range m.repoCommits {
@@ -239,12 +604,13 @@ func (m *Model) checkLoadMore() tea.Cmd {
 	commitsSoFar := 0
 	for _, rc := range m.repoCommits {
 		commitsSoFar += len(rc.Commits)
-		if m.cursor >= commitsSoFar-3 && rc.HasMore {
+		if m.cursor >= commitsSoFar-3 && rc.HasMore && !rc.Failed() {
 			m.loading = true
 			return func() tea.Msg {
 				return LoadMoreMsg{
-					RepoName: rc.Repository.NameWithOwner,
-					NextPage: rc.Page + 1,
+					RepoName: rc.Repository.ID(),
+					NextPage: rc.NextPage,
+					Cursor:   rc.Cursor,
 				}
 			}
 		}
@@ -260,15 +626,93 @@ func (m *Model) loadMoreForCurrentRepo() tea.Cmd {
 	commitsSoFar := 0
 	for _, rc := range m.repoCommits {
 		commitsSoFar += len(rc.Commits)
-		if m.cursor < commitsSoFar && rc.HasMore {
+		if m.cursor < commitsSoFar && rc.HasMore && !rc.Failed() {
 			m.loading = true
 			return func() tea.Msg {
 				return LoadMoreMsg{
-					RepoName: rc.Repository.NameWithOwner,
-					NextPage: rc.Page + 1,
+					RepoName: rc.Repository.ID(),
+					NextPage: rc.NextPage,
+					Cursor:   rc.Cursor,
 				}
 			}
 		}
 	}
 	return nil
 }
+
+func (m Model) renderRateLimit() string {
+	if !m.rateLimit.Known() {
+		return ""
+	}
+
+	quota := fmt.Sprintf("API: %s/%s", formatCount(m.rateLimit.Remaining), formatCount(m.rateLimit.Limit))
+	if m.rateLimit.IsLow() {
+		return tui.HelpStyle.Foreground(tui.ColorWarning).Render(quota)
+	}
+	return tui.HelpStyle.Render(quota)
+}
+
+func (m Model) currentRepo() (models.Repository, bool) {
+	commitsSoFar := 0
+	for _, rc := range m.repoCommits {
+		commitsSoFar += len(rc.Commits)
+		if m.cursor < commitsSoFar {
+			return rc.Repository, true
+		}
+	}
+	if len(m.repoCommits) > 0 {
+		return m.repoCommits[0].Repository, true
+	}
+	return models.Repository{}, false
+}
+
+func (m Model) failedRepos() []string {
+	var names []string
+	for _, rc := range m.repoCommits {
+		if rc.Failed() {
+			names = append(names, rc.Repository.ID())
+		}
+	}
+	return names
+}
+
+func (m Model) retryFailed() tea.Cmd {
+	names := m.failedRepos()
+	if len(names) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return RetryMsg{RepoNames: names}
+	}
+}
+
+func commitCountLabel(rc models.RepoCommits) string {
+	switch rc.Status {
+	case models.RepoStatusLoading:
+		return "loading"
+	case models.RepoStatusFailed:
+		if len(rc.Commits) == 0 {
+			return "failed"
+		}
+	}
+	if rc.TotalCount > len(rc.Commits) {
+		return fmt.Sprintf("%s of %s commits", formatCount(len(rc.Commits)), formatCount(rc.TotalCount))
+	}
+	return fmt.Sprintf("%s commits", formatCount(len(rc.Commits)))
+}
+
+func formatCount(n int) string {
+	if n < 0 {
+		return "-" + formatCount(-n)
+	}
+	digits := strconv.Itoa(n)
+
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
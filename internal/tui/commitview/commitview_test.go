@@ -1,7 +1,12 @@
 package commitview
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/tkozakas/gh-log/internal/models"
 )
@@ -90,3 +95,298 @@ func TestModelToggleExpanded(t *testing.T) {
 		t.Error("expected commit 2 to be collapsed")
 	}
 }
+
+func TestFormatCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		n        int
+		expected string
+	}{
+		{"zero", 0, "0"},
+		{"small", 50, "50"},
+		{"thousands", 1234, "1,234"},
+		{"millions", 1234567, "1,234,567"},
+		{"negative", -1234, "-1,234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatCount(tt.n); got != tt.expected {
+				t.Errorf("formatCount() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCommitCountLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		rc       models.RepoCommits
+		expected string
+	}{
+		{"noTotal", models.RepoCommits{Commits: make([]models.Commit, 3)}, "3 commits"},
+		{"allLoaded", models.RepoCommits{Commits: make([]models.Commit, 3), TotalCount: 3}, "3 commits"},
+		{"partial", models.RepoCommits{Commits: make([]models.Commit, 50), TotalCount: 1234}, "50 of 1,234 commits"},
+		{"loading", models.RepoCommits{Status: models.RepoStatusLoading}, "loading"},
+		{"failed", models.RepoCommits{Status: models.RepoStatusFailed}, "failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commitCountLabel(tt.rc); got != tt.expected {
+				t.Errorf("commitCountLabel() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateCommitsKeepsCursorOnSameCommit(t *testing.T) {
+	one := models.Repository{NameWithOwner: "owner/one"}
+	two := models.Repository{NameWithOwner: "owner/two"}
+	m := New([]models.RepoCommits{
+		{Repository: one, Status: models.RepoStatusLoading},
+		{Repository: two, Commits: []models.Commit{{SHA: "a"}, {SHA: "b"}}},
+	}, 80, 24)
+	m.cursor = 1
+	m.expanded[1] = true
+
+	m.UpdateCommits([]models.RepoCommits{
+		{Repository: one, Commits: []models.Commit{{SHA: "x"}, {SHA: "y"}, {SHA: "z"}}},
+		{Repository: two, Commits: []models.Commit{{SHA: "a"}, {SHA: "b"}}},
+	})
+
+	if m.cursor != 4 {
+		t.Errorf("cursor = %d, want 4", m.cursor)
+	}
+	if !m.expanded[4] || m.expanded[1] {
+		t.Errorf("expanded = %v, want only 4", m.expanded)
+	}
+}
+
+func TestRetryFailed(t *testing.T) {
+	m := Model{repoCommits: []models.RepoCommits{
+		{Repository: models.Repository{NameWithOwner: "owner/ok"}},
+		{Repository: models.Repository{NameWithOwner: "owner/bad"}, Status: models.RepoStatusFailed, Err: errors.New("404 Not Found")},
+	}}
+
+	msg, ok := m.retryFailed()().(RetryMsg)
+	if !ok {
+		t.Fatal("expected RetryMsg")
+	}
+	if len(msg.RepoNames) != 1 || msg.RepoNames[0] != "owner/bad" {
+		t.Errorf("RepoNames = %v, want [owner/bad]", msg.RepoNames)
+	}
+}
+
+func TestRetryFailedNoFailures(t *testing.T) {
+	m := Model{repoCommits: []models.RepoCommits{{Repository: models.Repository{NameWithOwner: "owner/ok"}}}}
+	if cmd := m.retryFailed(); cmd != nil {
+		t.Error("expected no command without failed repos")
+	}
+}
+
+func TestOfflineMarksPartialHistory(t *testing.T) {
+	m := New([]models.RepoCommits{{
+		Repository: models.Repository{NameWithOwner: "owner/repo"},
+		Branch:     "main",
+		Commits:    []models.Commit{{SHA: "abc1234", Message: "fix"}},
+		SyncedAt:   time.Now().Add(-3 * time.Hour),
+		Partial:    true,
+	}}, 120, 40)
+	m.SetOffline(true)
+
+	view := m.View()
+	for _, want := range []string{"synced 3 hours ago", "not cached (offline)", "offline"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q", want)
+		}
+	}
+}
+
+func TestSaveWorkspacePrompt(t *testing.T) {
+	m := New([]models.RepoCommits{{Repository: models.Repository{NameWithOwner: "owner/repo"}}}, 120, 40)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	if m.prompt != promptWorkspace {
+		t.Fatal("expected w to open the workspace name prompt")
+	}
+	for _, r := range " payments " {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.prompt != promptNone {
+		t.Error("expected enter to close the prompt")
+	}
+	msg, ok := cmd().(SaveWorkspaceMsg)
+	if !ok || msg.Name != "payments" {
+		t.Errorf("enter = %#v, want SaveWorkspaceMsg{payments}", cmd())
+	}
+}
+
+func TestMultiBranchBadges(t *testing.T) {
+	m := New([]models.RepoCommits{{
+		Repository: models.Repository{NameWithOwner: "owner/repo"},
+		Branch:     "main",
+		Branches:   []string{"main", "release/1.2"},
+		Commits: []models.Commit{
+			{SHA: "abc1234", Message: "fix", Branches: []string{"main", "release/1.2"}},
+			{SHA: "def5678", Message: "feature", Branches: []string{"main"}},
+		},
+	}}, 120, 40)
+
+	view := m.View()
+	for _, want := range []string{"(main, release/1.2)", "release/1.2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q", want)
+		}
+	}
+	if got := strings.Count(view, "main"); got != 3 {
+		t.Errorf("View() shows main %d times, want the header and a badge per commit", got)
+	}
+}
+
+func TestAllBranchesFlagsUnmergedCommits(t *testing.T) {
+	m := New([]models.RepoCommits{{
+		Repository:  models.Repository{NameWithOwner: "owner/repo", DefaultBranchName: "main"},
+		Branch:      "main",
+		AllBranches: true,
+		Branches:    []string{"main", "a", "b", "c", "d"},
+		Unmerged:    map[string]bool{"abc1234": true},
+		Commits: []models.Commit{
+			{SHA: "abc1234", Message: "wip", Branches: []string{"feature"}},
+			{SHA: "def5678", Message: "base", Branches: []string{"main", "a", "b", "c", "d"}},
+		},
+	}}, 120, 40)
+
+	view := m.View()
+	for _, want := range []string{"all branches: 5", "not on main", "+2"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q", want)
+		}
+	}
+	if got := strings.Count(view, "not on main"); got != 1 {
+		t.Errorf("View() flags %d commits, want 1", got)
+	}
+}
+
+func TestComparePrompt(t *testing.T) {
+	repo := models.Repository{NameWithOwner: "owner/repo", DefaultBranchName: "main"}
+	tests := []struct {
+		name  string
+		input string
+		base  string
+		head  string
+	}{
+		{"headOnly", "release/1.2", "main", "release/1.2"},
+		{"baseAndHead", "v1.0...v1.1", "v1.0", "v1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := New([]models.RepoCommits{{Repository: repo, Commits: []models.Commit{{SHA: "abc1234"}}}}, 120, 40)
+			m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+			if m.prompt != promptCompare {
+				t.Fatal("expected c to open the compare prompt")
+			}
+			m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.input)})
+
+			_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			msg, ok := cmd().(CompareMsg)
+			if !ok || msg.Repo.ID() != "owner/repo" || msg.Base != tt.base || msg.Head != tt.head {
+				t.Errorf("enter = %#v, want CompareMsg{%s...%s}", cmd(), tt.base, tt.head)
+			}
+		})
+	}
+}
+
+func TestComparisonView(t *testing.T) {
+	files := make([]models.FileChange, maxFiles+2)
+	for i := range files {
+		files[i] = models.FileChange{Filename: "file.go", Status: "modified", Additions: 1}
+	}
+	files[0] = models.FileChange{Filename: "docs/new.md", PreviousFilename: "docs/old.md", Status: "renamed"}
+	comparison := models.Comparison{
+		Base:     "main",
+		Head:     "release/1.2",
+		Status:   models.CompareDiverged,
+		AheadBy:  2,
+		BehindBy: 1,
+		Commits:  []models.Commit{{SHA: "abc1234", Message: "prepare release"}, {SHA: "def5678", Message: "bump"}},
+		Files:    files,
+	}
+	m := NewComparison(models.Repository{NameWithOwner: "owner/repo"}, comparison, 120, 60)
+
+	view := m.View()
+	for _, want := range []string{"Compare", "main...release/1.2 • diverged", "2 ahead, 1 behind", "docs/old.md → docs/new.md", "2 more files", "prepare release", "esc: back"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q", want)
+		}
+	}
+	if strings.Contains(view, "w: save workspace") {
+		t.Error("View() offers saving a comparison as a workspace")
+	}
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if _, ok := cmd().(BackMsg); !ok {
+		t.Errorf("esc = %#v, want BackMsg", cmd())
+	}
+
+	m.SetStandalone(true)
+	if strings.Contains(m.View(), "esc: back") {
+		t.Error("View() offers esc: back without a commit list to return to")
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc}); cmd != nil {
+		if _, ok := cmd().(BackMsg); ok {
+			t.Error("esc should not go back from a standalone comparison")
+		}
+	}
+}
+
+func TestExpandLoadsCommitDetail(t *testing.T) {
+	repo := models.Repository{NameWithOwner: "owner/repo"}
+	m := New([]models.RepoCommits{{
+		Repository: repo,
+		Branch:     "main",
+		Commits:    []models.Commit{{SHA: "abc1234def", Message: "fix handler"}},
+	}}, 120, 40)
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg, ok := cmd().(LoadDetailMsg)
+	if !ok || msg.Repo.ID() != "owner/repo" || msg.SHA != "abc1234def" {
+		t.Fatalf("enter = %#v, want LoadDetailMsg", cmd())
+	}
+	if !strings.Contains(m.View(), "loading changes...") {
+		t.Error("View() should show that changes are loading")
+	}
+
+	m.SetDetail("owner/repo", "abc1234def", models.Commit{}, errors.New("boom"))
+	if !strings.Contains(m.View(), "could not load changes: boom") {
+		t.Error("View() should show the detail error")
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Error("expanding again after an error should retry")
+	}
+
+	m.SetDetail("owner/repo", "abc1234def", models.Commit{
+		SHA:     "abc1234def",
+		Parents: []string{"fedcba9876"},
+		Files: []models.FileChange{
+			{Filename: "api/handler.go", Status: "modified", Additions: 12, Deletions: 2},
+			{Filename: "docs/new.md", PreviousFilename: "docs/old.md", Status: "renamed"},
+		},
+		Stats: &models.CommitStats{Additions: 12, Deletions: 2},
+	}, nil)
+	view := m.View()
+	for _, want := range []string{"Parents: fedcba9", "2 files changed, +12 -2", "api/handler.go", "+12", "docs/old.md → docs/new.md"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() missing %q", want)
+		}
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("expanding a commit with details should not fetch again")
+	}
+}
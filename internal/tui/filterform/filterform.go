@@ -2,6 +2,7 @@ package filterform
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -25,20 +26,26 @@ const (
 
 type RepoBranches struct {
 	Repo     models.Repository
-	Branches []string
+	Branches []models.Branch
+	Err      error
 }
 
 type Model struct {
 	inputs       []textinput.Model
 	repoBranches []RepoBranches
-	branchIdx    []int
+	selected     [][]string
+	allBranches  bool
 	focused      int
 	fieldCount   int
 }
 
 type DoneMsg struct {
 	Filters  models.FilterOptions
-	Branches map[string]string
+	Branches map[string][]string
+}
+
+type PickBranchMsg struct {
+	Index int
 }
 
 func New(repoBranches []RepoBranches) Model {
@@ -54,15 +61,15 @@ func New(repoBranches []RepoBranches) Model {
 
 	inputs[fieldDateFrom].Focus()
 
-	branchIdx := make([]int, len(repoBranches))
+	selected := make([][]string, len(repoBranches))
 	for i, rb := range repoBranches {
-		branchIdx[i] = findDefaultBranchIndex(rb)
+		selected[i] = []string{defaultBranch(rb)}
 	}
 
 	return Model{
 		inputs:       inputs,
 		repoBranches: repoBranches,
-		branchIdx:    branchIdx,
+		selected:     selected,
 		fieldCount:   fieldCount,
 	}
 }
@@ -77,8 +84,18 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		switch {
 		case key.Matches(msg, tui.Keys.Quit):
 			return m, tea.Quit
+		case key.Matches(msg, tui.Keys.Confirm) && m.isBranchField():
+			index := m.focused - fieldCountBase
+			return m, func() tea.Msg { return PickBranchMsg{Index: index} }
 		case key.Matches(msg, tui.Keys.Back), key.Matches(msg, tui.Keys.Confirm):
 			return m, m.submit
+		case key.Matches(msg, tui.Keys.AllBranches):
+			m.allBranches = !m.allBranches
+			if m.allBranches && m.isBranchField() {
+				m.focused = fieldDateFrom
+				m.focusCurrent()
+			}
+			return m, nil
 		case key.Matches(msg, tui.Keys.Tab):
 			return m.nextField(), nil
 		case key.Matches(msg, tui.Keys.ShiftTab):
@@ -95,7 +112,10 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 
 func (m Model) View() string {
 	title := tui.TitleStyle.Render("Configure Filters")
-	help := tui.HelpStyle.Render("tab: next • shift+tab: prev • ↑/↓: cycle branch • enter: confirm")
+	help := tui.HelpStyle.Render("tab: next • shift+tab: prev • ctrl+b: all branches • enter: confirm")
+	if m.isBranchField() {
+		help = tui.HelpStyle.Render("tab: next • shift+tab: prev • enter: pick branch • ↑/↓: cycle branch • ctrl+b: all branches • esc: confirm")
+	}
 
 	var b strings.Builder
 	b.WriteString(title)
@@ -107,7 +127,10 @@ func (m Model) View() string {
 	b.WriteString(fmt.Sprintf("  %s\n\n", m.renderField(fieldPerPage, "Per page:")))
 	b.WriteString(fmt.Sprintf("  %s  %s\n\n", m.renderField(fieldSemanticQuery, "Semantic:"), m.renderSemanticStatus()))
 
-	if len(m.repoBranches) > 0 {
+	if m.allBranches {
+		b.WriteString("  " + tui.DimStyle.Render("─── Branches ───") + "\n\n")
+		b.WriteString("  " + tui.SelectedStyle.Render("All branches") + tui.DimStyle.Render(" • commits not on the default branch are flagged") + "\n")
+	} else if len(m.repoBranches) > 0 {
 		b.WriteString("  " + tui.DimStyle.Render("─── Branches ───") + "\n\n")
 		for i, rb := range m.repoBranches {
 			b.WriteString(fmt.Sprintf("  %s\n", m.renderBranchField(i, rb)))
@@ -128,24 +151,37 @@ func (m Model) Filters() models.FilterOptions {
 		Author:        m.inputs[fieldAuthor].Value(),
 		PerPage:       perPage,
 		SemanticQuery: m.inputs[fieldSemanticQuery].Value(),
+		AllBranches:   m.allBranches,
 	}
 	filters.Validate()
 	return filters
 }
 
-func (m Model) Branches() map[string]string {
-	result := make(map[string]string)
+func (m Model) Branches() map[string][]string {
+	result := make(map[string][]string)
 	for i, rb := range m.repoBranches {
-		idx := m.branchIdx[i]
-		if idx >= 0 && idx < len(rb.Branches) {
-			result[rb.Repo.NameWithOwner] = rb.Branches[idx]
-		} else {
-			result[rb.Repo.NameWithOwner] = rb.Repo.DefaultBranchName
-		}
+		result[rb.Repo.ID()] = slices.Clone(m.selected[i])
 	}
 	return result
 }
 
+func (m *Model) SetBranches(repoID string, branches []string) {
+	for i, rb := range m.repoBranches {
+		if rb.Repo.ID() != repoID {
+			continue
+		}
+		var known []string
+		for _, name := range branches {
+			if slices.ContainsFunc(rb.Branches, func(b models.Branch) bool { return b.Name == name }) {
+				known = append(known, name)
+			}
+		}
+		if len(known) > 0 {
+			m.selected[i] = known
+		}
+	}
+}
+
 func (m Model) isBranchField() bool {
 	return m.focused >= fieldCountBase
 }
@@ -161,20 +197,25 @@ func (m Model) cycleBranch(msg tea.KeyMsg) Model {
 		return m
 	}
 
+	current := slices.IndexFunc(m.repoBranches[repoIdx].Branches, func(b models.Branch) bool {
+		return b.Name == m.selected[repoIdx][0]
+	})
 	if key.Matches(msg, tui.Keys.Down) {
-		m.branchIdx[repoIdx] = (m.branchIdx[repoIdx] + 1) % branchCount
+		current = (current + 1) % branchCount
 	} else {
-		m.branchIdx[repoIdx]--
-		if m.branchIdx[repoIdx] < 0 {
-			m.branchIdx[repoIdx] = branchCount - 1
+		current--
+		if current < 0 {
+			current = branchCount - 1
 		}
 	}
+	m.selected = slices.Clone(m.selected)
+	m.selected[repoIdx] = []string{m.repoBranches[repoIdx].Branches[current].Name}
 	return m
 }
 
 func (m Model) nextField() Model {
 	m.blurCurrent()
-	m.focused = (m.focused + 1) % m.fieldCount
+	m.focused = (m.focused + 1) % m.activeFieldCount()
 	m.focusCurrent()
 	return m
 }
@@ -183,12 +224,19 @@ func (m Model) prevField() Model {
 	m.blurCurrent()
 	m.focused--
 	if m.focused < 0 {
-		m.focused = m.fieldCount - 1
+		m.focused = m.activeFieldCount() - 1
 	}
 	m.focusCurrent()
 	return m
 }
 
+func (m Model) activeFieldCount() int {
+	if m.allBranches {
+		return fieldCountBase
+	}
+	return m.fieldCount
+}
+
 func (m *Model) blurCurrent() {
 	if m.focused < fieldCountBase {
 		m.inputs[m.focused].Blur()
@@ -237,26 +285,28 @@ func (m Model) renderBranchField(repoIdx int, rb RepoBranches) string {
 		labelStyle = tui.SelectedStyle
 	}
 
-	branchName := rb.Repo.DefaultBranchName
-	if m.branchIdx[repoIdx] >= 0 && m.branchIdx[repoIdx] < len(rb.Branches) {
-		branchName = rb.Branches[m.branchIdx[repoIdx]]
-	}
-
-	isDefault := branchName == rb.Repo.DefaultBranchName
-	branchDisplay := branchName
-	if isDefault {
-		branchDisplay += " (default)"
+	names := make([]string, len(m.selected[repoIdx]))
+	for i, name := range m.selected[repoIdx] {
+		names[i] = name
+		if name == rb.Repo.DefaultBranchName {
+			names[i] += " (default)"
+		}
 	}
+	branchDisplay := strings.Join(names, ", ")
 
 	cursor := "  "
 	if isFocused {
 		cursor = "> "
 	}
 
-	return fmt.Sprintf("%s%s %s",
+	field := fmt.Sprintf("%s%s %s",
 		cursor,
-		labelStyle.Render(rb.Repo.NameWithOwner+":"),
+		labelStyle.Render(rb.Repo.ID()+":"),
 		tui.CommitSHAStyle.Render(branchDisplay))
+	if rb.Err != nil {
+		field += " " + tui.ErrorStyle.Render(fmt.Sprintf("(could not list branches: %v)", rb.Err))
+	}
+	return field
 }
 
 func (m Model) submit() tea.Msg {
@@ -266,13 +316,11 @@ func (m Model) submit() tea.Msg {
 	}
 }
 
-func findDefaultBranchIndex(rb RepoBranches) int {
-	for i, b := range rb.Branches {
-		if b == rb.Repo.DefaultBranchName {
-			return i
-		}
+func defaultBranch(rb RepoBranches) string {
+	if rb.Repo.DefaultBranchName == "" && len(rb.Branches) > 0 {
+		return rb.Branches[0].Name
 	}
-	return 0
+	return rb.Repo.DefaultBranchName
 }
 
 func newInput(placeholder string, width int) textinput.Model {
@@ -1,8 +1,11 @@
 package filterform
 
 import (
+	"slices"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
+
 	"github.com/tkozakas/gh-log/internal/models"
 )
 
@@ -19,16 +22,16 @@ func TestNew(t *testing.T) {
 
 func TestNewWithBranches(t *testing.T) {
 	repos := []RepoBranches{
-		{Repo: models.Repository{NameWithOwner: "org/repo1", DefaultBranchName: "main"}, Branches: []string{"main", "dev"}},
-		{Repo: models.Repository{NameWithOwner: "org/repo2", DefaultBranchName: "master"}, Branches: []string{"master"}},
+		{Repo: models.Repository{NameWithOwner: "org/repo1", DefaultBranchName: "main"}, Branches: branches("main", "dev")},
+		{Repo: models.Repository{NameWithOwner: "org/repo2", DefaultBranchName: "master"}, Branches: branches("master")},
 	}
 	m := New(repos)
 
 	if m.fieldCount != fieldCountBase+2 {
 		t.Errorf("fieldCount = %d, want %d", m.fieldCount, fieldCountBase+2)
 	}
-	if len(m.branchIdx) != 2 {
-		t.Errorf("branchIdx length = %d, want 2", len(m.branchIdx))
+	if len(m.selected) != 2 {
+		t.Errorf("selected length = %d, want 2", len(m.selected))
 	}
 }
 
@@ -68,21 +71,70 @@ func TestFiltersValidation(t *testing.T) {
 
 func TestBranches(t *testing.T) {
 	repos := []RepoBranches{
-		{Repo: models.Repository{NameWithOwner: "org/repo1", DefaultBranchName: "main"}, Branches: []string{"main", "dev"}},
+		{Repo: models.Repository{NameWithOwner: "org/repo1", DefaultBranchName: "main"}, Branches: branches("main", "dev")},
 	}
 	m := New(repos)
 
 	branches := m.Branches()
 
-	if branches["org/repo1"] != "main" {
-		t.Errorf("branch = %q, want %q", branches["org/repo1"], "main")
+	if !slices.Equal(branches["org/repo1"], []string{"main"}) {
+		t.Errorf("branches = %v, want %v", branches["org/repo1"], []string{"main"})
 	}
 
-	m.branchIdx[0] = 1
+	m.focused = fieldCountBase
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
 	branches = m.Branches()
 
-	if branches["org/repo1"] != "dev" {
-		t.Errorf("branch = %q, want %q", branches["org/repo1"], "dev")
+	if !slices.Equal(branches["org/repo1"], []string{"dev"}) {
+		t.Errorf("branches = %v, want %v", branches["org/repo1"], []string{"dev"})
+	}
+}
+
+func TestConfirmOnBranchFieldPicksBranch(t *testing.T) {
+	repos := []RepoBranches{
+		{Repo: models.Repository{NameWithOwner: "org/repo1", DefaultBranchName: "main"}, Branches: branches("main", "dev")},
+		{Repo: models.Repository{NameWithOwner: "org/repo2", DefaultBranchName: "main"}, Branches: branches("main", "release")},
+	}
+	m := New(repos)
+	m.focused = fieldCountBase + 1
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	msg, ok := cmd().(PickBranchMsg)
+	if !ok || msg.Index != 1 {
+		t.Fatalf("enter on branch field = %#v, want PickBranchMsg{1}", cmd())
+	}
+
+	m.SetBranches("org/repo2", []string{"main", "release", "missing"})
+	if got := m.Branches()["org/repo2"]; !slices.Equal(got, []string{"main", "release"}) {
+		t.Errorf("branches = %v, want [main release]", got)
+	}
+	m.SetBranches("org/repo2", []string{"missing"})
+	if got := m.Branches()["org/repo2"]; !slices.Equal(got, []string{"main", "release"}) {
+		t.Errorf("unknown branches should be ignored, got %v", got)
+	}
+}
+
+func TestAllBranchesToggle(t *testing.T) {
+	repos := []RepoBranches{
+		{Repo: models.Repository{NameWithOwner: "org/repo1", DefaultBranchName: "main"}, Branches: branches("main", "dev")},
+	}
+	m := New(repos)
+	m.focused = fieldCountBase
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if !m.Filters().AllBranches {
+		t.Fatal("expected ctrl+b to enable all-branches mode")
+	}
+	if m.focused != fieldDateFrom {
+		t.Errorf("focused = %d, want the first field once branch fields are hidden", m.focused)
+	}
+	if m = m.prevField(); m.focused != fieldCountBase-1 {
+		t.Errorf("focused = %d, want %d (branch fields skipped)", m.focused, fieldCountBase-1)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyCtrlB})
+	if m.Filters().AllBranches {
+		t.Error("expected a second ctrl+b to turn all-branches mode off")
 	}
 }
 
@@ -116,3 +168,11 @@ func TestPrevField(t *testing.T) {
 		t.Errorf("focused = %d, want %d", m.focused, m.fieldCount-2)
 	}
 }
+
+func branches(names ...string) []models.Branch {
+	result := make([]models.Branch, len(names))
+	for i, name := range names {
+		result[i] = models.Branch{Name: name}
+	}
+	return result
+}
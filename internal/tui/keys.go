@@ -3,19 +3,33 @@ package tui
 import "github.com/charmbracelet/bubbles/key"
 
 type KeyMap struct {
-	Up       key.Binding
-	Down     key.Binding
-	Select   key.Binding
-	Confirm  key.Binding
-	Back     key.Binding
-	Quit     key.Binding
-	Search   key.Binding
-	NextPage key.Binding
-	PrevPage key.Binding
-	Restart  key.Binding
-	Default  key.Binding
-	Tab      key.Binding
-	ShiftTab key.Binding
+	Up           key.Binding
+	Down         key.Binding
+	Select       key.Binding
+	Confirm      key.Binding
+	Back         key.Binding
+	Quit         key.Binding
+	Search       key.Binding
+	NextPage     key.Binding
+	PrevPage     key.Binding
+	Restart      key.Binding
+	Retry        key.Binding
+	Default      key.Binding
+	Tab          key.Binding
+	ShiftTab     key.Binding
+	Facet        key.Binding
+	Add          key.Binding
+	HideForks    key.Binding
+	HideArchived key.Binding
+	Sort         key.Binding
+	SelectAll    key.Binding
+	Invert       key.Binding
+	Clear        key.Binding
+	SelectOwner  key.Binding
+	Review       key.Binding
+	Save         key.Binding
+	AllBranches  key.Binding
+	Compare      key.Binding
 }
 
 var Keys = KeyMap{
@@ -59,6 +73,10 @@ var Keys = KeyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "restart"),
 	),
+	Retry: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "retry"),
+	),
 	Default: key.NewBinding(
 		key.WithKeys("d"),
 		key.WithHelp("d", "default"),
@@ -71,6 +89,58 @@ var Keys = KeyMap{
 		key.WithKeys("shift+tab"),
 		key.WithHelp("shift+tab", "prev field"),
 	),
+	Facet: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "owner/source"),
+	),
+	Add: key.NewBinding(
+		key.WithKeys("a"),
+		key.WithHelp("a", "add repo"),
+	),
+	HideForks: key.NewBinding(
+		key.WithKeys("F"),
+		key.WithHelp("F", "hide forks"),
+	),
+	HideArchived: key.NewBinding(
+		key.WithKeys("A"),
+		key.WithHelp("A", "hide archived"),
+	),
+	Sort: key.NewBinding(
+		key.WithKeys("s"),
+		key.WithHelp("s", "sort"),
+	),
+	SelectAll: key.NewBinding(
+		key.WithKeys("ctrl+a"),
+		key.WithHelp("ctrl+a", "select visible"),
+	),
+	Invert: key.NewBinding(
+		key.WithKeys("i"),
+		key.WithHelp("i", "invert"),
+	),
+	Clear: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "clear"),
+	),
+	SelectOwner: key.NewBinding(
+		key.WithKeys("O"),
+		key.WithHelp("O", "select owner"),
+	),
+	Review: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "review"),
+	),
+	Save: key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "save workspace"),
+	),
+	AllBranches: key.NewBinding(
+		key.WithKeys("ctrl+b"),
+		key.WithHelp("ctrl+b", "all branches"),
+	),
+	Compare: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "compare"),
+	),
 }
 
 func (k KeyMap) ShortHelp() []key.Binding {
@@ -2,9 +2,12 @@ package reposelect
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
@@ -19,43 +22,110 @@ type item struct {
 
 func (i item) Title() string {
 	checkbox := "[ ]"
-	if _, ok := i.selected[i.repo.NameWithOwner]; ok {
+	if _, ok := i.selected[i.repo.ID()]; ok {
 		checkbox = "[x]"
 	}
-	return fmt.Sprintf("%s %s", checkbox, i.repo.NameWithOwner)
+	return fmt.Sprintf("%s %s", checkbox, i.repo.ID())
 }
 
 func (i item) Description() string {
-	desc := i.repo.TimeSincePush()
+	parts := i.repo.Tags()
+	if i.repo.PrimaryLanguage != "" {
+		parts = append(parts, i.repo.PrimaryLanguage)
+	}
+	if i.repo.Stars > 0 {
+		parts = append(parts, fmt.Sprintf("★ %d", i.repo.Stars))
+	}
 	if i.repo.Description != "" {
-		desc = i.repo.Description + " • " + desc
+		parts = append(parts, i.repo.Description)
 	}
-	return desc
+	return strings.Join(append(parts, i.repo.TimeSincePush()), " • ")
 }
 
 func (i item) FilterValue() string {
-	return i.repo.NameWithOwner
+	return i.repo.ID()
+}
+
+type facet struct {
+	label string
+	match func(models.Repository) bool
+}
+
+type sortMode int
+
+const (
+	sortPushed sortMode = iota
+	sortName
+	sortStars
+	sortModeCount
+)
+
+func (s sortMode) String() string {
+	switch s {
+	case sortName:
+		return "name"
+	case sortStars:
+		return "stars"
+	default:
+		return "recently pushed"
+	}
+}
+
+func (s sortMode) compare(a, b models.Repository) int {
+	switch s {
+	case sortName:
+		return strings.Compare(strings.ToLower(a.ID()), strings.ToLower(b.ID()))
+	case sortStars:
+		return b.Stars - a.Stars
+	default:
+		return b.PushedAt.Compare(a.PushedAt)
+	}
 }
 
 type Model struct {
-	list     list.Model
-	selected map[string]models.Repository
-	width    int
-	height   int
+	list         list.Model
+	repos        []models.Repository
+	selected     map[string]models.Repository
+	facets       []facet
+	facet        int
+	sort         sortMode
+	hideForks    bool
+	hideArchived bool
+	input        textinput.Model
+	adding       bool
+	searchQuery  string
+	reviewing    bool
+	status       string
+	notice       string
+	width        int
+	height       int
 }
 
 type DoneMsg struct {
 	Selected []models.Repository
 }
 
+type LookupMsg struct {
+	Query string
+	Exact bool
+}
+
+type LookupResultMsg struct {
+	Query string
+	Exact bool
+	Repos []models.Repository
+	Err   error
+}
+
+type AddedMsg struct {
+	Repo models.Repository
+}
+
+const title = "Select Repositories"
+
 func New(repos []models.Repository, width, height int) Model {
 	selected := make(map[string]models.Repository)
 
-	listItems := make([]list.Item, len(repos))
-	for i, r := range repos {
-		listItems[i] = item{repo: r, selected: selected}
-	}
-
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
 		Foreground(tui.ColorPrimary).
@@ -64,8 +134,8 @@ func New(repos []models.Repository, width, height int) Model {
 		Foreground(tui.ColorSecondary).
 		BorderLeftForeground(tui.ColorPrimary)
 
-	l := list.New(listItems, delegate, width, height-4)
-	l.Title = "Select Repositories"
+	l := list.New(nil, delegate, width, height-4)
+	l.Title = title
 	l.SetShowStatusBar(true)
 	l.SetFilteringEnabled(true)
 	l.Styles.Title = tui.TitleStyle
@@ -75,12 +145,205 @@ func New(repos []models.Repository, width, height int) Model {
 	l.AdditionalShortHelpKeys = shortHelpKeys
 	l.AdditionalFullHelpKeys = fullHelpKeys
 
-	return Model{
+	input := textinput.New()
+	input.Placeholder = "owner/repo or search terms"
+	input.Width = 40
+	input.Prompt = "Add repository: "
+
+	m := Model{
 		list:     l,
+		repos:    repos,
 		selected: selected,
+		facets:   buildFacets(repos),
+		input:    input,
 		width:    width,
 		height:   height,
 	}
+	m.refresh()
+	return m
+}
+
+func buildFacets(repos []models.Repository) []facet {
+	facets := []facet{{label: "all", match: func(models.Repository) bool { return true }}}
+	for _, source := range slices.Concat(models.RepoSources, []models.RepoSource{models.SourceSaved}) {
+		if slices.ContainsFunc(repos, func(r models.Repository) bool { return r.HasSource(source) }) {
+			facets = append(facets, facet{
+				label: "source: " + string(source),
+				match: func(r models.Repository) bool { return r.HasSource(source) },
+			})
+		}
+	}
+
+	var owners []string
+	for _, r := range repos {
+		if owner := r.Owner(); owner != "" && !slices.Contains(owners, owner) {
+			owners = append(owners, owner)
+		}
+	}
+	slices.Sort(owners)
+	for _, owner := range owners {
+		facets = append(facets, facet{
+			label: "owner: " + owner,
+			match: func(r models.Repository) bool { return r.Owner() == owner },
+		})
+	}
+	return facets
+}
+
+func (m *Model) refresh() {
+	f := m.facets[m.facet]
+	var repos []models.Repository
+	for _, r := range m.repos {
+		if f.match(r) && !(m.hideForks && r.IsFork) && !(m.hideArchived && r.IsArchived) {
+			repos = append(repos, r)
+		}
+	}
+	slices.SortStableFunc(repos, m.sort.compare)
+
+	listItems := make([]list.Item, len(repos))
+	for i, r := range repos {
+		listItems[i] = item{repo: r, selected: m.selected}
+	}
+	m.list.SetItems(listItems)
+	m.list.ResetSelected()
+	m.list.Title = m.title()
+}
+
+func (m Model) title() string {
+	parts := []string{title}
+	if m.facet > 0 {
+		parts = append(parts, m.facets[m.facet].label)
+	}
+	if m.sort != sortPushed {
+		parts = append(parts, "by "+m.sort.String())
+	}
+	if m.hideForks {
+		parts = append(parts, "no forks")
+	}
+	if m.hideArchived {
+		parts = append(parts, "no archived")
+	}
+	return strings.Join(parts, " · ")
+}
+
+func (m Model) showSearchResults(query string, results []models.Repository) Model {
+	listItems := make([]list.Item, len(results))
+	for i, r := range results {
+		listItems[i] = item{repo: r, selected: m.selected}
+	}
+	m.searchQuery = query
+	m.list.SetItems(listItems)
+	m.list.ResetSelected()
+	m.list.ResetFilter()
+	m.list.Title = fmt.Sprintf("Search results for %q", query)
+	m.status = "space/enter: add • esc: back"
+	return m
+}
+
+func (m Model) closeSearchResults() Model {
+	m.searchQuery = ""
+	m.status = ""
+	m.refresh()
+	return m
+}
+
+func (m Model) addRepo(repo models.Repository) (Model, tea.Cmd) {
+	if m.searchQuery != "" {
+		m = m.closeSearchResults()
+	}
+
+	m.status = "Selected " + repo.ID()
+	if i := slices.IndexFunc(m.repos, func(r models.Repository) bool { return r.ID() == repo.ID() }); i >= 0 {
+		m.selected[repo.ID()] = m.repos[i]
+		return m, nil
+	}
+
+	repo.Sources = append(slices.Clone(repo.Sources), models.SourceSaved)
+	m.repos = append([]models.Repository{repo}, m.repos...)
+	m.selected[repo.ID()] = repo
+	m.facets = buildFacets(m.repos)
+	m.facet = 0
+	m.refresh()
+	m.status = "Added " + repo.ID()
+	return m, func() tea.Msg { return AddedMsg{Repo: repo} }
+}
+
+func (m Model) handleLookup(msg LookupResultMsg) (Model, tea.Cmd) {
+	switch {
+	case msg.Err != nil && len(msg.Repos) == 0:
+		m.status = fmt.Sprintf("Could not add %s: %v", msg.Query, msg.Err)
+		return m, nil
+	case len(msg.Repos) == 0:
+		m.status = fmt.Sprintf("No repositories match %q", msg.Query)
+		return m, nil
+	case msg.Exact:
+		return m.addRepo(msg.Repos[0])
+	}
+
+	m = m.showSearchResults(msg.Query, msg.Repos)
+	if msg.Err != nil {
+		m.status += fmt.Sprintf(" • some hosts could not be searched: %v", msg.Err)
+	}
+	return m, nil
+}
+
+func (m Model) updateInput(msg tea.KeyMsg) (Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, tui.Keys.Back):
+		m.adding = false
+		m.input.Blur()
+		return m, nil
+	case key.Matches(msg, tui.Keys.Confirm):
+		query := strings.TrimSpace(m.input.Value())
+		if query == "" {
+			return m, nil
+		}
+		m.adding = false
+		m.input.Blur()
+		m.input.Reset()
+		m.status = "Looking up " + query + "..."
+		return m, func() tea.Msg { return LookupMsg{Query: query, Exact: isNameWithOwner(query)} }
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func isNameWithOwner(query string) bool {
+	if strings.ContainsAny(query, " \t") {
+		return false
+	}
+	owner, name, ok := strings.Cut(query, "/")
+	return ok && owner != "" && name != "" && !strings.HasSuffix(name, "/")
+}
+
+func (m Model) nextSort() Model {
+	m.sort = (m.sort + 1) % sortModeCount
+	m.refresh()
+	return m
+}
+
+func (m Model) toggleForks() Model {
+	m.hideForks = !m.hideForks
+	m.refresh()
+	return m
+}
+
+func (m Model) toggleArchived() Model {
+	m.hideArchived = !m.hideArchived
+	m.refresh()
+	return m
+}
+
+func (m Model) nextFacet() Model {
+	m.facet = (m.facet + 1) % len(m.facets)
+	m.refresh()
+	return m
+}
+
+func (m *Model) SetNotice(notice string) {
+	m.notice = notice
 }
 
 func (m Model) Init() tea.Cmd {
@@ -95,18 +358,26 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 		m.list.SetSize(msg.Width, msg.Height-4)
 		return m, nil
 
+	case LookupResultMsg:
+		return m.handleLookup(msg)
+
 	case tea.KeyMsg:
+		if m.adding {
+			return m.updateInput(msg)
+		}
 		if m.list.FilterState() != list.Filtering {
+			var handled bool
+			var cmd tea.Cmd
 			switch {
-			case key.Matches(msg, tui.Keys.Select):
-				m = m.toggleSelection()
-				return m, nil
-			case key.Matches(msg, tui.Keys.Confirm):
-				if len(m.selected) > 0 {
-					return m, m.confirmSelection
-				}
-			case key.Matches(msg, tui.Keys.Quit):
-				return m, tea.Quit
+			case m.searchQuery != "":
+				m, cmd, handled = m.updateSearchResults(msg)
+			case m.reviewing:
+				m, cmd, handled = m.updateReview(msg)
+			default:
+				m, cmd, handled = m.updateBrowse(msg)
+			}
+			if handled {
+				return m, cmd
 			}
 		}
 	}
@@ -116,13 +387,97 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m Model) updateBrowse(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, tui.Keys.Add):
+		m.adding = true
+		m.status = ""
+		return m, m.input.Focus(), true
+	case key.Matches(msg, tui.Keys.Select):
+		return m.toggleSelection(), nil, true
+	case key.Matches(msg, tui.Keys.SelectAll):
+		return m.selectVisible(), nil, true
+	case key.Matches(msg, tui.Keys.Invert):
+		return m.invertVisible(), nil, true
+	case key.Matches(msg, tui.Keys.Clear):
+		return m.clearSelection(), nil, true
+	case key.Matches(msg, tui.Keys.SelectOwner):
+		return m.selectOwner(), nil, true
+	case key.Matches(msg, tui.Keys.Review):
+		return m.showReview(), nil, true
+	case key.Matches(msg, tui.Keys.Facet):
+		return m.nextFacet(), nil, true
+	case key.Matches(msg, tui.Keys.Sort):
+		return m.nextSort(), nil, true
+	case key.Matches(msg, tui.Keys.HideForks):
+		return m.toggleForks(), nil, true
+	case key.Matches(msg, tui.Keys.HideArchived):
+		return m.toggleArchived(), nil, true
+	case key.Matches(msg, tui.Keys.Confirm):
+		if len(m.selected) > 0 {
+			return m, m.confirmSelection, true
+		}
+	case key.Matches(msg, tui.Keys.Quit):
+		return m, tea.Quit, true
+	}
+	return m, nil, false
+}
+
+func (m Model) updateSearchResults(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, tui.Keys.Back):
+		return m.closeSearchResults(), nil, true
+	case key.Matches(msg, tui.Keys.Select), key.Matches(msg, tui.Keys.Confirm):
+		if it, ok := m.list.SelectedItem().(item); ok {
+			m, cmd := m.addRepo(it.repo)
+			return m, cmd, true
+		}
+		return m, nil, true
+	case key.Matches(msg, tui.Keys.Quit):
+		return m, tea.Quit, true
+	}
+	return m, nil, false
+}
+
+func (m Model) updateReview(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch {
+	case key.Matches(msg, tui.Keys.Back), key.Matches(msg, tui.Keys.Review):
+		return m.closeReview(), nil, true
+	case key.Matches(msg, tui.Keys.Select):
+		if it, ok := m.list.SelectedItem().(item); ok {
+			delete(m.selected, it.repo.ID())
+			m = m.showReview()
+		}
+		return m, nil, true
+	case key.Matches(msg, tui.Keys.Clear):
+		return m.clearSelection().showReview(), nil, true
+	case key.Matches(msg, tui.Keys.Confirm):
+		if len(m.selected) > 0 {
+			return m, m.confirmSelection, true
+		}
+		return m, nil, true
+	case key.Matches(msg, tui.Keys.Quit):
+		return m, tea.Quit, true
+	}
+	return m, nil, false
+}
+
 func (m Model) View() string {
 	status := fmt.Sprintf("\n  %d selected", len(m.selected))
 	if len(m.selected) > 0 {
-		status = tui.SelectedStyle.Render(status)
+		status = tui.SelectedStyle.Render(status+": ") + tui.DimStyle.Render(m.selectionSummary())
 	} else {
 		status = tui.DimStyle.Render(status)
 	}
+	if m.notice != "" {
+		status += tui.WarningStyle.Render("  " + m.notice)
+	}
+	if m.status != "" {
+		status += tui.DimStyle.Render("  " + m.status)
+	}
+	if m.adding {
+		status += "\n  " + m.input.View()
+	}
 	return m.list.View() + status
 }
 
@@ -141,7 +496,7 @@ func (m Model) toggleSelection() Model {
 	}
 
 	it := selectedItem.(item)
-	repoKey := it.repo.NameWithOwner
+	repoKey := it.repo.ID()
 
 	if _, exists := m.selected[repoKey]; exists {
 		delete(m.selected, repoKey)
@@ -152,14 +507,97 @@ func (m Model) toggleSelection() Model {
 	return m
 }
 
+func (m Model) selectVisible() Model {
+	for _, it := range m.list.VisibleItems() {
+		repo := it.(item).repo
+		m.selected[repo.ID()] = repo
+	}
+	return m
+}
+
+func (m Model) invertVisible() Model {
+	for _, it := range m.list.VisibleItems() {
+		repo := it.(item).repo
+		if _, ok := m.selected[repo.ID()]; ok {
+			delete(m.selected, repo.ID())
+		} else {
+			m.selected[repo.ID()] = repo
+		}
+	}
+	return m
+}
+
+func (m Model) clearSelection() Model {
+	clear(m.selected)
+	return m
+}
+
+func (m Model) selectOwner() Model {
+	current, ok := m.list.SelectedItem().(item)
+	if !ok {
+		return m
+	}
+	owner := current.repo.Owner()
+	for _, it := range m.list.VisibleItems() {
+		if repo := it.(item).repo; repo.Owner() == owner {
+			m.selected[repo.ID()] = repo
+		}
+	}
+	m.status = "Selected all visible repositories owned by " + owner
+	return m
+}
+
+func (m Model) showReview() Model {
+	repos := m.Selected()
+	slices.SortFunc(repos, func(a, b models.Repository) int { return strings.Compare(a.ID(), b.ID()) })
+
+	listItems := make([]list.Item, len(repos))
+	for i, r := range repos {
+		listItems[i] = item{repo: r, selected: m.selected}
+	}
+	index := m.list.Index()
+	m.reviewing = true
+	m.list.ResetFilter()
+	m.list.SetItems(listItems)
+	m.list.Select(min(index, max(len(listItems)-1, 0)))
+	m.list.Title = fmt.Sprintf("Review selection (%d)", len(repos))
+	m.status = "space: remove • x: clear • enter: confirm • esc: back"
+	return m
+}
+
+func (m Model) closeReview() Model {
+	m.reviewing = false
+	m.status = ""
+	m.refresh()
+	return m
+}
+
+func (m Model) selectionSummary() string {
+	const maxNames = 3
+	names := make([]string, 0, len(m.selected))
+	for id := range m.selected {
+		names = append(names, id)
+	}
+	slices.Sort(names)
+
+	summary := strings.Join(names[:min(len(names), maxNames)], ", ")
+	if len(names) > maxNames {
+		summary += fmt.Sprintf(", +%d more", len(names)-maxNames)
+	}
+	return summary
+}
+
 func (m Model) confirmSelection() tea.Msg {
 	return DoneMsg{Selected: m.Selected()}
 }
 
 func shortHelpKeys() []key.Binding {
-	return []key.Binding{tui.Keys.Select, tui.Keys.Confirm}
+	return []key.Binding{tui.Keys.Select, tui.Keys.SelectAll, tui.Keys.Review, tui.Keys.Confirm, tui.Keys.Facet, tui.Keys.Add}
 }
 
 func fullHelpKeys() []key.Binding {
-	return []key.Binding{tui.Keys.Select, tui.Keys.Confirm, tui.Keys.Quit}
+	return []key.Binding{
+		tui.Keys.Select, tui.Keys.SelectAll, tui.Keys.Invert, tui.Keys.Clear, tui.Keys.SelectOwner, tui.Keys.Review, tui.Keys.Confirm,
+		tui.Keys.Facet, tui.Keys.Sort, tui.Keys.HideForks, tui.Keys.HideArchived, tui.Keys.Add, tui.Keys.Quit,
+	}
 }
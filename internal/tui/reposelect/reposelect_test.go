@@ -1,7 +1,13 @@
 package reposelect
 
 import (
+	"errors"
+	"slices"
+	"strings"
 	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/tkozakas/gh-log/internal/models"
 )
@@ -115,3 +121,298 @@ func TestSharedMapBetweenItems(t *testing.T) {
 		}
 	}
 }
+
+func TestFacetCycle(t *testing.T) {
+	repos := []models.Repository{
+		{NameWithOwner: "acme/api", Sources: []models.RepoSource{models.SourceOrg, models.SourceTeam}},
+		{NameWithOwner: "me/dotfiles", Sources: []models.RepoSource{models.SourceOwner, models.SourceStarred}},
+		{NameWithOwner: "acme/web", Sources: []models.RepoSource{models.SourceOrg}},
+	}
+	m := New(repos, 80, 24)
+
+	expected := []struct {
+		label string
+		count int
+	}{
+		{"all", 3},
+		{"source: owner", 1},
+		{"source: org", 2},
+		{"source: team", 1},
+		{"source: starred", 1},
+		{"owner: acme", 2},
+		{"owner: me", 1},
+		{"all", 3},
+	}
+	for i, want := range expected {
+		if i > 0 {
+			m = m.nextFacet()
+		}
+		if got := m.facets[m.facet].label; got != want.label {
+			t.Fatalf("step %d: facet = %q, want %q", i, got, want.label)
+		}
+		if got := len(m.list.Items()); got != want.count {
+			t.Errorf("%s: %d items, want %d", want.label, got, want.count)
+		}
+	}
+}
+
+func TestFacetKeepsSelection(t *testing.T) {
+	repos := []models.Repository{{NameWithOwner: "acme/api"}, {NameWithOwner: "me/dotfiles"}}
+	m := New(repos, 80, 24)
+	m = m.toggleSelection()
+
+	for range len(m.facets) {
+		m = m.nextFacet()
+	}
+	if len(m.Selected()) != 1 || m.list.Items()[0].(item).Title() != "[x] acme/api" {
+		t.Errorf("selection lost after cycling facets: %v", m.Selected())
+	}
+}
+
+func TestIsNameWithOwner(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"golang/go", true},
+		{"gitlab.example.com/group/project", true},
+		{"bubble tea", false},
+		{"golang", false},
+		{"golang/", false},
+		{"/go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := isNameWithOwner(tt.query); got != tt.expected {
+				t.Errorf("isNameWithOwner(%q) = %v, want %v", tt.query, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLookupAddsAndSelectsRepo(t *testing.T) {
+	m := New([]models.Repository{{NameWithOwner: "me/dotfiles"}}, 80, 24)
+	upstream := models.Repository{NameWithOwner: "golang/go"}
+
+	m, cmd := m.Update(LookupResultMsg{Query: "golang/go", Exact: true, Repos: []models.Repository{upstream}})
+	if cmd == nil {
+		t.Fatal("expected AddedMsg command")
+	}
+	added, ok := cmd().(AddedMsg)
+	if !ok || added.Repo.NameWithOwner != "golang/go" || !added.Repo.HasSource(models.SourceSaved) {
+		t.Errorf("cmd() = %+v, want AddedMsg for golang/go", added)
+	}
+	if len(m.list.Items()) != 2 || m.list.Items()[0].(item).Title() != "[x] golang/go" {
+		t.Errorf("golang/go should be listed first and selected")
+	}
+
+	m, cmd = m.Update(LookupResultMsg{Query: "golang/go", Exact: true, Repos: []models.Repository{upstream}})
+	if cmd != nil || len(m.list.Items()) != 2 {
+		t.Errorf("adding an existing repo should not duplicate it")
+	}
+}
+
+func TestSearchResultsAddHighlightedRepo(t *testing.T) {
+	m := New([]models.Repository{{NameWithOwner: "me/dotfiles"}}, 80, 24)
+	results := []models.Repository{{NameWithOwner: "charmbracelet/bubbletea"}, {NameWithOwner: "charmbracelet/bubbles"}}
+
+	m, _ = m.Update(LookupResultMsg{Query: "bubble", Repos: results})
+	if m.searchQuery != "bubble" || len(m.list.Items()) != 2 {
+		t.Fatalf("expected search results, got %d items", len(m.list.Items()))
+	}
+
+	m, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("expected AddedMsg command")
+	}
+	if m.searchQuery != "" || len(m.list.Items()) != 2 || len(m.Selected()) != 1 {
+		t.Errorf("search results should close with charmbracelet/bubbletea added and selected")
+	}
+}
+
+func TestLookupFailureKeepsList(t *testing.T) {
+	m := New([]models.Repository{{NameWithOwner: "me/dotfiles"}}, 80, 24)
+
+	m, cmd := m.Update(LookupResultMsg{Query: "golang/nope", Exact: true, Err: errors.New("not found")})
+	if cmd != nil || len(m.list.Items()) != 1 {
+		t.Errorf("failed lookup should leave the list unchanged")
+	}
+	if !strings.Contains(m.View(), "Could not add golang/nope") {
+		t.Errorf("View() should report the failed lookup")
+	}
+}
+
+func TestPartialSearchShowsResults(t *testing.T) {
+	m := New([]models.Repository{{NameWithOwner: "me/dotfiles"}}, 120, 24)
+	results := []models.Repository{{NameWithOwner: "charmbracelet/bubbletea"}}
+
+	m, _ = m.Update(LookupResultMsg{Query: "bubble", Repos: results, Err: errors.New("gitlab.example.com: unauthorized")})
+	if m.searchQuery != "bubble" || len(m.list.Items()) != 1 {
+		t.Fatalf("expected search results, got %d items", len(m.list.Items()))
+	}
+	if !strings.Contains(m.status, "some hosts could not be searched: gitlab.example.com: unauthorized") {
+		t.Errorf("status = %q, want a warning about the failed host", m.status)
+	}
+}
+
+func TestItemDescriptionMetadata(t *testing.T) {
+	repo := models.Repository{
+		Description:     "Terminal UI framework",
+		PrimaryLanguage: "Go",
+		Stars:           27000,
+		IsFork:          true,
+		IsPrivate:       true,
+	}
+	got := item{repo: repo}.Description()
+
+	if !strings.HasPrefix(got, "private • fork • Go • ★ 27000 • Terminal UI framework • ") {
+		t.Errorf("Description() = %q", got)
+	}
+}
+
+func TestSortAndHideToggles(t *testing.T) {
+	now := time.Now()
+	repos := []models.Repository{
+		{NameWithOwner: "acme/zeta", PushedAt: now, Stars: 5},
+		{NameWithOwner: "acme/alpha", PushedAt: now.Add(-time.Hour), Stars: 50, IsFork: true},
+		{NameWithOwner: "acme/mid", PushedAt: now.Add(-2 * time.Hour), Stars: 10, IsArchived: true},
+	}
+	m := New(repos, 80, 24)
+
+	names := func(m Model) []string {
+		var names []string
+		for _, it := range m.list.Items() {
+			names = append(names, it.(item).repo.NameWithOwner)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name     string
+		apply    func(Model) Model
+		expected []string
+		title    string
+	}{
+		{"pushed", func(m Model) Model { return m }, []string{"acme/zeta", "acme/alpha", "acme/mid"}, "Select Repositories"},
+		{"name", Model.nextSort, []string{"acme/alpha", "acme/mid", "acme/zeta"}, "Select Repositories · by name"},
+		{"stars", Model.nextSort, []string{"acme/alpha", "acme/mid", "acme/zeta"}, "Select Repositories · by stars"},
+		{"hideForks", Model.toggleForks, []string{"acme/mid", "acme/zeta"}, "Select Repositories · by stars · no forks"},
+		{"hideArchived", Model.toggleArchived, []string{"acme/zeta"}, "Select Repositories · by stars · no forks · no archived"},
+		{"backToPushed", Model.nextSort, []string{"acme/zeta"}, "Select Repositories · no forks · no archived"},
+	}
+
+	for _, tt := range tests {
+		m = tt.apply(m)
+		if got := names(m); !slices.Equal(got, tt.expected) {
+			t.Errorf("%s: items = %v, want %v", tt.name, got, tt.expected)
+		}
+		if m.list.Title != tt.title {
+			t.Errorf("%s: title = %q, want %q", tt.name, m.list.Title, tt.title)
+		}
+	}
+}
+
+func bulkTestModel() Model {
+	return New([]models.Repository{
+		{NameWithOwner: "acme/api"},
+		{NameWithOwner: "acme/web"},
+		{NameWithOwner: "tools/api-lint"},
+		{NameWithOwner: "me/dotfiles"},
+	}, 80, 24)
+}
+
+func selectedIDs(m Model) []string {
+	var ids []string
+	for _, r := range m.Selected() {
+		ids = append(ids, r.ID())
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestBulkSelection(t *testing.T) {
+	tests := []struct {
+		name     string
+		apply    func(Model) Model
+		expected []string
+	}{
+		{
+			name: "selectVisibleRespectsFilter",
+			apply: func(m Model) Model {
+				m.list.SetFilterText("api")
+				return m.selectVisible()
+			},
+			expected: []string{"acme/api", "tools/api-lint"},
+		},
+		{
+			name: "invertVisible",
+			apply: func(m Model) Model {
+				m = m.toggleSelection()
+				return m.invertVisible()
+			},
+			expected: []string{"acme/web", "me/dotfiles", "tools/api-lint"},
+		},
+		{
+			name: "clear",
+			apply: func(m Model) Model {
+				return m.selectVisible().clearSelection()
+			},
+			expected: nil,
+		},
+		{
+			name: "selectOwner",
+			apply: func(m Model) Model {
+				return m.selectOwner()
+			},
+			expected: []string{"acme/api", "acme/web"},
+		},
+		{
+			name: "selectOwnerRespectsFilter",
+			apply: func(m Model) Model {
+				m.list.SetFilterText("api")
+				return m.selectOwner()
+			},
+			expected: []string{"acme/api"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tt.apply(bulkTestModel())
+			if got := selectedIDs(m); !slices.Equal(got, tt.expected) {
+				t.Errorf("selected = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReviewRemovesEntries(t *testing.T) {
+	m := bulkTestModel().selectVisible()
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if !m.reviewing || len(m.list.Items()) != 4 || m.list.Title != "Review selection (4)" {
+		t.Fatalf("expected review of 4 repos, got %q", m.list.Title)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune(" ")})
+	if got := selectedIDs(m); !slices.Equal(got, []string{"acme/web", "me/dotfiles", "tools/api-lint"}) {
+		t.Errorf("selected = %v, want acme/api removed", got)
+	}
+	if len(m.list.Items()) != 3 {
+		t.Errorf("review list has %d items, want 3", len(m.list.Items()))
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.reviewing || len(m.list.Items()) != 4 {
+		t.Errorf("esc should return to the full list")
+	}
+}
+
+func TestSelectionSummary(t *testing.T) {
+	m := bulkTestModel().selectVisible()
+
+	if got := m.selectionSummary(); got != "acme/api, acme/web, me/dotfiles, +1 more" {
+		t.Errorf("selectionSummary() = %q", got)
+	}
+}
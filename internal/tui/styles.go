@@ -30,6 +30,9 @@ var (
 	SuccessStyle = lipgloss.NewStyle().
 			Foreground(ColorSuccess)
 
+	WarningStyle = lipgloss.NewStyle().
+			Foreground(ColorWarning)
+
 	HelpStyle = lipgloss.NewStyle().
 			Foreground(ColorSecondary).
 			MarginTop(1)
@@ -48,6 +51,11 @@ var (
 	CommitAuthorStyle = lipgloss.NewStyle().
 				Foreground(ColorPrimary)
 
+	BranchBadgeStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("0")).
+				Background(ColorSecondary).
+				Padding(0, 1)
+
 	RepoHeaderStyle = lipgloss.NewStyle().
 			Bold(true).
 			Foreground(ColorPrimary).
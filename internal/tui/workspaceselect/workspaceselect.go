@@ -0,0 +1,147 @@
+package workspaceselect
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tkozakas/gh-log/internal/tui"
+	"github.com/tkozakas/gh-log/internal/workspace"
+)
+
+type item struct {
+	workspace workspace.Workspace
+	isNew     bool
+}
+
+func (i item) Title() string {
+	if i.isNew {
+		return "New selection"
+	}
+	return i.workspace.Name
+}
+
+func (i item) Description() string {
+	if i.isNew {
+		return "Pick repositories from the full list"
+	}
+	return describe(i.workspace)
+}
+
+func (i item) FilterValue() string {
+	return i.Title()
+}
+
+type Model struct {
+	list list.Model
+}
+
+type DoneMsg struct {
+	Workspace workspace.Workspace
+}
+
+type NewSelectionMsg struct{}
+
+func New(workspaces []workspace.Workspace, width, height int) Model {
+	items := make([]list.Item, 0, len(workspaces)+1)
+	for _, w := range workspaces {
+		items = append(items, item{workspace: w})
+	}
+	items = append(items, item{isNew: true})
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Foreground(tui.ColorPrimary).
+		BorderLeftForeground(tui.ColorPrimary)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Foreground(tui.ColorSecondary).
+		BorderLeftForeground(tui.ColorPrimary)
+
+	l := list.New(items, delegate, width, height-4)
+	l.Title = "Open Workspace"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(true)
+	l.Styles.Title = tui.TitleStyle
+
+	return Model{list: l}
+}
+
+func (m Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetSize(msg.Width, msg.Height-4)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+
+		switch {
+		case key.Matches(msg, tui.Keys.Confirm):
+			if selected, ok := m.list.SelectedItem().(item); ok {
+				return m, submit(selected)
+			}
+		case key.Matches(msg, tui.Keys.Quit):
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m Model) View() string {
+	return m.list.View()
+}
+
+func submit(selected item) tea.Cmd {
+	return func() tea.Msg {
+		if selected.isNew {
+			return NewSelectionMsg{}
+		}
+		return DoneMsg{Workspace: selected.workspace}
+	}
+}
+
+func describe(w workspace.Workspace) string {
+	parts := []string{pluralize(len(w.Repos), "repo")}
+	if len(w.Branches) > 0 {
+		parts = append(parts, pluralize(len(w.Branches), "branch override"))
+	}
+
+	f := w.Filters
+	switch {
+	case f.DateFrom != "" && f.DateTo != "":
+		parts = append(parts, f.DateFrom+" to "+f.DateTo)
+	case f.DateFrom != "":
+		parts = append(parts, "since "+f.DateFrom)
+	case f.DateTo != "":
+		parts = append(parts, "until "+f.DateTo)
+	}
+	if f.AllBranches {
+		parts = append(parts, "all branches")
+	}
+	if f.Author != "" {
+		parts = append(parts, "by "+f.Author)
+	}
+	if f.SemanticQuery != "" {
+		parts = append(parts, fmt.Sprintf("%q", f.SemanticQuery))
+	}
+	return strings.Join(parts, " • ")
+}
+
+func pluralize(count int, noun string) string {
+	if count == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
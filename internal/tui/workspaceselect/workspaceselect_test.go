@@ -0,0 +1,57 @@
+package workspaceselect
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/tkozakas/gh-log/internal/models"
+	"github.com/tkozakas/gh-log/internal/workspace"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name      string
+		workspace workspace.Workspace
+		expected  string
+	}{
+		{"empty", workspace.Workspace{}, "0 repos"},
+		{
+			name: "full",
+			workspace: workspace.Workspace{
+				Repos:    []models.Repository{{NameWithOwner: "acme/gateway"}, {NameWithOwner: "acme/ledger"}},
+				Branches: map[string][]string{"acme/gateway": {"release"}},
+				Filters:  models.FilterOptions{DateFrom: "2024-01-01", Author: "dana", SemanticQuery: "retries"},
+			},
+			expected: `2 repos • 1 branch override • since 2024-01-01 • by dana • "retries"`,
+		},
+		{
+			name:      "dateRange",
+			workspace: workspace.Workspace{Repos: []models.Repository{{}}, Filters: models.FilterOptions{DateFrom: "2024-01-01", DateTo: "2024-03-31"}},
+			expected:  "1 repo • 2024-01-01 to 2024-03-31",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := describe(tt.workspace); got != tt.expected {
+				t.Errorf("describe() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConfirm(t *testing.T) {
+	m := New([]workspace.Workspace{{Name: "payments"}, {Name: "mobile"}}, 80, 24)
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if msg, ok := cmd().(DoneMsg); !ok || msg.Workspace.Name != "payments" {
+		t.Errorf("enter on first item = %#v, want DoneMsg for payments", cmd())
+	}
+
+	m.list.Select(2)
+	_, cmd = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if _, ok := cmd().(NewSelectionMsg); !ok {
+		t.Errorf("enter on last item = %#v, want NewSelectionMsg", cmd())
+	}
+}
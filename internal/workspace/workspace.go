@@ -0,0 +1,96 @@
+package workspace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tkozakas/gh-log/internal/atomicfile"
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+var ErrNotFound = errors.New("workspace not found")
+
+type Workspace struct {
+	Name     string               `json:"name"`
+	Repos    []models.Repository  `json:"repos"`
+	Branches map[string][]string  `json:"branches,omitempty"`
+	Filters  models.FilterOptions `json:"filters"`
+}
+
+type Store struct {
+	path string
+}
+
+func DefaultPath() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "ghlog", "workspaces.json"), nil
+}
+
+func New(path string) *Store {
+	return &Store{path: path}
+}
+
+func (s *Store) List() ([]Workspace, error) {
+	var workspaces []Workspace
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &workspaces); err != nil {
+		return nil, fmt.Errorf("%s: %w", s.path, err)
+	}
+	return workspaces, nil
+}
+
+func (s *Store) Get(name string) (Workspace, error) {
+	workspaces, err := s.List()
+	if err != nil {
+		return Workspace{}, err
+	}
+	if i := index(workspaces, name); i >= 0 {
+		return workspaces[i], nil
+	}
+	return Workspace{}, fmt.Errorf("%q: %w", name, ErrNotFound)
+}
+
+func (s *Store) Save(w Workspace) error {
+	w.Name = strings.TrimSpace(w.Name)
+	if w.Name == "" {
+		return errors.New("workspace name is required")
+	}
+
+	workspaces, err := s.List()
+	if err != nil {
+		return err
+	}
+	if i := index(workspaces, w.Name); i >= 0 {
+		workspaces[i] = w
+	} else {
+		workspaces = append(workspaces, w)
+	}
+	return s.write(workspaces)
+}
+
+func (s *Store) write(workspaces []Workspace) error {
+	data, err := json.MarshalIndent(workspaces, "", "  ")
+	if err != nil {
+		return err
+	}
+	return atomicfile.Write(s.path, append(data, '\n'))
+}
+
+func index(workspaces []Workspace, name string) int {
+	return slices.IndexFunc(workspaces, func(w Workspace) bool { return strings.EqualFold(w.Name, name) })
+}
@@ -0,0 +1,64 @@
+package workspace
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/tkozakas/gh-log/internal/models"
+)
+
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	return New(filepath.Join(t.TempDir(), "ghlog", "workspaces.json"))
+}
+
+func TestSaveAndGet(t *testing.T) {
+	s := newTestStore(t)
+	payments := Workspace{
+		Name:     "payments",
+		Repos:    []models.Repository{{NameWithOwner: "acme/gateway", DefaultBranchName: "main"}},
+		Branches: map[string][]string{"acme/gateway": {"main", "release/2.4"}},
+		Filters:  models.FilterOptions{Author: "dana", PerPage: 30},
+	}
+	if err := s.Save(payments); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+
+	got, err := s.Get("Payments")
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	if len(got.Repos) != 1 || len(got.Branches["acme/gateway"]) != 2 || got.Filters.Author != "dana" || got.Filters.PerPage != 30 {
+		t.Errorf("Get() = %+v", got)
+	}
+}
+
+func TestSaveReplacesByName(t *testing.T) {
+	s := newTestStore(t)
+	s.Save(Workspace{Name: "infra", Repos: []models.Repository{{NameWithOwner: "acme/terraform"}}})
+	s.Save(Workspace{Name: "mobile"})
+	s.Save(Workspace{Name: "infra", Repos: []models.Repository{{NameWithOwner: "acme/terraform"}, {NameWithOwner: "acme/ansible"}}})
+
+	workspaces, err := s.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(workspaces) != 2 || workspaces[0].Name != "infra" || len(workspaces[0].Repos) != 2 {
+		t.Errorf("List() = %+v", workspaces)
+	}
+}
+
+func TestMissingAndInvalid(t *testing.T) {
+	s := newTestStore(t)
+
+	if workspaces, err := s.List(); err != nil || len(workspaces) != 0 {
+		t.Errorf("List() on missing file = %v, %v", workspaces, err)
+	}
+	if _, err := s.Get("payments"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() error = %v, want ErrNotFound", err)
+	}
+	if err := s.Save(Workspace{Name: "  "}); err == nil {
+		t.Error("Save() should reject an empty name")
+	}
+}